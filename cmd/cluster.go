@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"log/slog"
+	"net/url"
+
+	"github.com/Patrick-Ivann/AIM-Q/internal/cli"
+	"github.com/Patrick-Ivann/AIM-Q/internal/rabbitmq"
+	"github.com/Patrick-Ivann/AIM-Q/internal/redact"
+)
+
+// loadClusters fetches the topologies of several brokers and merges them,
+// tagging every object with the name of its cluster.
+//
+// Topologies can only be merged from live brokers, so opts must not name a
+// definitions export or a snapshot.
+func loadClusters(
+	ctx context.Context, log *slog.Logger, brokers []cli.Options, opts cli.Options,
+) (*rabbitmq.Topology, error) {
+	if opts.DefinitionsFile != "" || opts.SnapshotFile != "" {
+		return nil, fmt.Errorf("several --uri or --context cannot be combined with --from-definitions or --snapshot")
+	}
+
+	topologies := make([]*rabbitmq.Topology, 0, len(brokers))
+	seen := make(map[string]bool, len(brokers))
+	for _, broker := range brokers {
+		topology, name, host, err := loadCluster(ctx, log, broker)
+		if err != nil {
+			return nil, err
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("several brokers belong to cluster %q", name)
+		}
+		seen[name] = true
+
+		topology.SetCluster(name, host)
+		topologies = append(topologies, topology)
+	}
+	return rabbitmq.Merge(topologies...), nil
+}
+
+// loadCluster fetches the topology of one broker and returns it with the name of
+// its cluster: the context name for brokers selected with --context, the cluster
+// name reported by the broker otherwise; and with the host name of the broker.
+func loadCluster(ctx context.Context, log *slog.Logger, broker cli.Options) (*rabbitmq.Topology, string, string, error) {
+	opts, err := resolveConnection(broker)
+	if err != nil {
+		return nil, "", "", err
+	}
+	if opts.URI == "" {
+		return nil, "", "", fmt.Errorf("context %q has no uri", broker.Context)
+	}
+	client, err := newClient(opts)
+	if err != nil {
+		return nil, "", "", err
+	}
+	host := ""
+	if u, err := url.Parse(opts.URI); err == nil {
+		host = u.Hostname()
+	}
+	log.Info("connecting to RabbitMQ", "uri", redact.URI(opts.URI))
+
+	name := broker.Context
+	if name == "" {
+		overview, err := client.FetchOverviewContext(ctx)
+		if err != nil {
+			return nil, "", "", fmt.Errorf("fetch error: %w", err)
+		}
+		name = cmp.Or(overview.ClusterName, client.BaseURL())
+	}
+
+	topology, err := client.FetchTopologyContext(ctx)
+	if err != nil {
+		return nil, "", "", fmt.Errorf("fetch error from %s: %w", name, err)
+	}
+	return topology, name, host, nil
+}
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/Patrick-Ivann/AIM-Q/internal/config"
+	"github.com/Patrick-Ivann/AIM-Q/internal/redact"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(contextCmd)
+	contextCmd.AddCommand(contextListCmd, contextUseCmd, contextCurrentCmd)
+}
+
+var contextCmd = &cobra.Command{
+	Use:   "context",
+	Short: "List and switch between the brokers of the config file",
+	Long: `Brokers listed under "contexts" in the config file can be selected with
+--context NAME on any command; the current context is used when --context is omitted.`,
+}
+
+var contextListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the contexts of the config file, marking the current one with *",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadConfig()
+		if err != nil {
+			return err
+		}
+		current := currentContext(cfg)
+
+		w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintln(w, "CURRENT\tNAME\tURI\tFILTER-VHOST")
+		for _, ctx := range cfg.Contexts {
+			marker := ""
+			if ctx.Name == current {
+				marker = "*"
+			}
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", marker, ctx.Name, redact.URI(ctx.URI), ctx.FilterVhost)
+		}
+		return w.Flush()
+	},
+}
+
+var contextUseCmd = &cobra.Command{
+	Use:   "use NAME",
+	Short: "Make NAME the current context of the config file",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := configPath()
+		if err != nil {
+			return err
+		}
+		if err := config.SetCurrentContext(path, args[0]); err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Switched to context %q.\n", args[0])
+		return nil
+	},
+}
+
+var contextCurrentCmd = &cobra.Command{
+	Use:   "current",
+	Short: "Print the name of the current context",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadConfig()
+		if err != nil {
+			return err
+		}
+		current := currentContext(cfg)
+		if current == "" {
+			return fmt.Errorf("no current context")
+		}
+		_, _ = fmt.Fprintln(cmd.OutOrStdout(), current)
+		return nil
+	},
+}
+
+// currentContext returns the context selected by $AIMQ_CONTEXT or, if unset, the config file.
+func currentContext(cfg *config.Config) string {
+	if name := os.Getenv(config.EnvContext); name != "" {
+		return name
+	}
+	return cfg.CurrentContext
+}
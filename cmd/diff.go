@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/Patrick-Ivann/AIM-Q/internal/cli"
+	"github.com/Patrick-Ivann/AIM-Q/internal/diagram"
+	"github.com/Patrick-Ivann/AIM-Q/internal/diff"
+	"github.com/spf13/cobra"
+)
+
+var (
+	diffOld string
+	diffNew string
+	diffOut string
+)
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+
+	diffCmd.Flags().StringVar(&diffOld, "old", "", "Snapshot of the topology before the change")
+	diffCmd.Flags().StringVar(&diffNew, "new", "", "Snapshot of the topology after the change")
+	addConnectionFlags(diffCmd, "Compare against the live broker instead of --new")
+	diffCmd.Flags().StringVar(&diffOut, "out", "diff.puml", "Output file path of the PlantUML diff diagram")
+}
+
+var diffCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Compare a snapshot with another snapshot or a live broker",
+	Long: `Compare two RabbitMQ topologies and report added, removed and changed
+exchanges, queues and bindings. The report is printed as text and a PlantUML
+diagram highlighting added (green) and removed (red) elements is written to --out.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if diffOld == "" {
+			return fmt.Errorf("missing --old")
+		}
+
+		before, err := loadTopology(cmd.Context(), cli.Options{SnapshotFile: diffOld})
+		if err != nil {
+			return err
+		}
+
+		newSource, err := connectionOptions()
+		if err != nil {
+			return err
+		}
+		newSource.SnapshotFile = diffNew
+		newSource, err = resolveConnection(newSource)
+		if err != nil {
+			return err
+		}
+		if err := validateSource(newSource); err != nil {
+			return fmt.Errorf("new topology: %w", err)
+		}
+		after, err := loadTopology(cmd.Context(), newSource)
+		if err != nil {
+			return err
+		}
+
+		result := diff.Compare(before, after)
+		if err := result.WriteText(cmd.OutOrStdout()); err != nil {
+			return err
+		}
+
+		if err := os.WriteFile(diffOut, []byte(diagram.GenerateDiff(result)), 0644); err != nil {
+			return fmt.Errorf("writing file failed: %w", err)
+		}
+		return nil
+	},
+}
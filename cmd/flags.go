@@ -0,0 +1,167 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/Patrick-Ivann/AIM-Q/internal/cli"
+	"github.com/Patrick-Ivann/AIM-Q/internal/config"
+	"github.com/Patrick-Ivann/AIM-Q/internal/rabbitmq"
+	"github.com/spf13/cobra"
+)
+
+var (
+	configFile   string
+	contextNames []string
+	uris         []string
+	username     string
+	passwordFile string
+
+	timeout    time.Duration
+	maxRetries int
+
+	caCert             string
+	clientCert         string
+	clientKey          string
+	insecureSkipVerify bool
+
+	token             string
+	tokenFile         string
+	oauthTokenURL     string
+	oauthClientID     string
+	oauthClientSecret string
+	oauthScopes       []string
+)
+
+// addConnectionFlags registers the flags configuring access to the management API.
+//
+// --context and --uri are repeatable for commands accepting several brokers,
+// see brokerOptions; other commands read them with connectionOptions.
+func addConnectionFlags(c *cobra.Command, uriUsage string) {
+	c.Flags().StringArrayVar(&contextNames, "context", nil,
+		"Broker context of the config file to use (env "+config.EnvContext+", default its current-context)")
+	c.Flags().StringArrayVar(&uris, "uri", nil, uriUsage+" (env "+config.EnvURI+")")
+	c.Flags().StringVar(&username, "username", "", "Management API user, overriding the one of --uri (env "+config.EnvUsername+")")
+	c.Flags().StringVar(&passwordFile, "password-file", "",
+		"File containing the management API password (env "+config.EnvPassword+" holds the password itself)")
+	c.Flags().DurationVar(&timeout, "timeout", 30*time.Second, "Maximum duration of a topology fetch (0 disables)")
+	c.Flags().IntVar(&maxRetries, "max-retries", rabbitmq.DefaultRetryPolicy.MaxRetries,
+		"Retries of a request failing with a server error, rate limiting or a connection error (0 disables)")
+
+	c.Flags().StringVar(&caCert, "ca-cert", "", "PEM file of the CA that signed the HTTPS management listener certificate")
+	c.Flags().StringVar(&clientCert, "client-cert", "", "PEM client certificate for mutual TLS (requires --client-key)")
+	c.Flags().StringVar(&clientKey, "client-key", "", "PEM private key of --client-cert")
+	c.Flags().BoolVar(&insecureSkipVerify, "insecure-skip-verify", false,
+		"Do not verify the management listener certificate (insecure, for testing only)")
+	c.MarkFlagsRequiredTogether("client-cert", "client-key")
+
+	c.Flags().StringVar(&token, "token", "", "OAuth2 bearer token sent instead of basic auth")
+	c.Flags().StringVar(&tokenFile, "token-file", "", "File containing an OAuth2 bearer token, re-read for every request")
+	c.Flags().StringVar(&oauthTokenURL, "oauth-token-url", "", "OAuth2 token endpoint for the client credentials flow")
+	c.Flags().StringVar(&oauthClientID, "oauth-client-id", "", "OAuth2 client ID (with --oauth-token-url)")
+	c.Flags().StringVar(&oauthClientSecret, "oauth-client-secret", "", "OAuth2 client secret (with --oauth-token-url)")
+	c.Flags().StringSliceVar(&oauthScopes, "oauth-scope", nil, "OAuth2 scope to request (repeatable)")
+	c.MarkFlagsMutuallyExclusive("token", "token-file", "oauth-token-url")
+	c.MarkFlagsRequiredTogether("oauth-token-url", "oauth-client-id")
+}
+
+// connectionOptions returns the options set by the connection flags, for
+// commands connecting to a single broker.
+func connectionOptions() (cli.Options, error) {
+	if len(contextNames) > 1 || len(uris) > 1 {
+		return cli.Options{}, fmt.Errorf("--context and --uri can only be given once for this command")
+	}
+	opts := sharedConnectionOptions()
+	opts.Context = firstOrEmpty(contextNames)
+	opts.URI = firstOrEmpty(uris)
+	return opts, nil
+}
+
+// brokerOptions returns the connection options of every broker selected by the
+// connection flags, for commands merging the topologies of several clusters.
+//
+// A single --context and --uri describe one broker, the URI overriding the one
+// of the context. When either flag is repeated, each --context and each --uri
+// is a separate broker sharing the other connection flags.
+func brokerOptions() []cli.Options {
+	if len(contextNames) <= 1 && len(uris) <= 1 {
+		opts, _ := connectionOptions()
+		return []cli.Options{opts}
+	}
+
+	var brokers []cli.Options
+	for _, name := range contextNames {
+		opts := sharedConnectionOptions()
+		opts.Context = name
+		brokers = append(brokers, opts)
+	}
+	for _, u := range uris {
+		opts := sharedConnectionOptions()
+		opts.URI = u
+		brokers = append(brokers, opts)
+	}
+	return brokers
+}
+
+// sharedConnectionOptions returns the options set by the connection flags other than --context and --uri.
+func sharedConnectionOptions() cli.Options {
+	return cli.Options{
+		Username:     username,
+		PasswordFile: passwordFile,
+		Timeout:      timeout,
+		MaxRetries:   maxRetries,
+
+		CACertFile:         caCert,
+		ClientCertFile:     clientCert,
+		ClientKeyFile:      clientKey,
+		InsecureSkipVerify: insecureSkipVerify,
+
+		Token:             token,
+		TokenFile:         tokenFile,
+		OAuthTokenURL:     oauthTokenURL,
+		OAuthClientID:     oauthClientID,
+		OAuthClientSecret: oauthClientSecret,
+		OAuthScopes:       oauthScopes,
+	}
+}
+
+// firstOrEmpty returns the first value, or "" if there is none.
+func firstOrEmpty(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+// resolveConnection completes the connection options set by flags from the
+// environment and the configuration file, unless the topology is read from a file.
+func resolveConnection(opts cli.Options) (cli.Options, error) {
+	if opts.DefinitionsFile != "" || opts.SnapshotFile != "" {
+		return opts, nil
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return opts, err
+	}
+	return config.Resolve(opts, cfg, os.Getenv)
+}
+
+// loadConfig reads the configuration file given by --config, or the default
+// one, which is optional.
+func loadConfig() (*config.Config, error) {
+	path, err := configPath()
+	if err != nil {
+		return nil, err
+	}
+	return config.Load(path, configFile != "")
+}
+
+// configPath returns the path of the configuration file given by --config, or the default one.
+func configPath() (string, error) {
+	if configFile != "" {
+		return configFile, nil
+	}
+	return config.DefaultPath()
+}
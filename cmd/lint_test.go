@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testDefinitions is a definitions export of a small topology.
+const testDefinitions = "../internal/rabbitmq/testdata/definitions.json"
+
+// execute runs the root command with args and returns what it printed to stdout and stderr.
+func execute(t *testing.T, args ...string) (string, string, error) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	rootCmd.SetArgs(args)
+	rootCmd.SetOut(&stdout)
+	rootCmd.SetErr(&stderr)
+	t.Cleanup(func() {
+		rootCmd.SetArgs(nil)
+		rootCmd.SetOut(nil)
+		rootCmd.SetErr(nil)
+	})
+	err := rootCmd.ExecuteContext(context.Background())
+	return stdout.String(), stderr.String(), err
+}
+
+func TestLintCmd_JSONOutput(t *testing.T) {
+	stdout, stderr, err := execute(t, "lint", "--from-definitions", testDefinitions, "--output", "json", "--fail-on", "none")
+	require.NoError(t, err)
+
+	var doc struct {
+		Findings []json.RawMessage `json:"findings"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(stdout), &doc), "stdout only holds the report")
+	assert.NotEmpty(t, doc.Findings)
+	assert.Contains(t, stderr, "reading definitions export")
+}
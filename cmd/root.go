@@ -1,6 +1,12 @@
 package cmd
 
 import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/Patrick-Ivann/AIM-Q/internal/config"
 	"github.com/spf13/cobra"
 )
 
@@ -9,8 +15,19 @@ var rootCmd = &cobra.Command{
 	Short: "AIM-Q generates PlantUML diagrams of RabbitMQ topologies",
 	Long: `AIM-Q connects to RabbitMQ management API and renders exchanges,
 bindings, queues and consumers in a visual PlantUML format.`,
+	// Errors are printed by main, after credentials have been redacted.
+	SilenceErrors: true,
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&configFile, "config", "",
+		"Configuration file (default $"+config.EnvConfig+" or ~/.config/aimq/config.yaml)")
 }
 
+// Execute runs the root command; an interrupt or termination signal cancels
+// the command context, aborting in-flight requests to the broker.
 func Execute() error {
-	return rootCmd.Execute()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return rootCmd.ExecuteContext(ctx)
 }
@@ -0,0 +1,19 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouteCmd_Output(t *testing.T) {
+	stdout, stderr, err := execute(t, "route", "--from-definitions", testDefinitions,
+		"--vhost", "orders", "--exchange", "orders", "--routing-key", "order.created.eu")
+	require.NoError(t, err)
+
+	assert.Equal(t, "1 queue(s) receive the message:\n"+
+		"  order.created\n"+
+		"    orders (topic) --[\"order.created.*\"]--> queue order.created\n", stdout)
+	assert.Contains(t, stderr, "reading definitions export")
+}
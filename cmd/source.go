@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/Patrick-Ivann/AIM-Q/internal/cli"
+	"github.com/Patrick-Ivann/AIM-Q/internal/config"
+	"github.com/Patrick-Ivann/AIM-Q/internal/rabbitmq"
+	"github.com/Patrick-Ivann/AIM-Q/internal/snapshot"
+)
+
+// validateSource checks that exactly one topology source is configured.
+func validateSource(opts cli.Options) error {
+	sources := 0
+	for _, s := range []string{opts.URI, opts.DefinitionsFile, opts.SnapshotFile} {
+		if s != "" {
+			sources++
+		}
+	}
+	switch {
+	case sources == 0:
+		return fmt.Errorf("missing --uri (or %s), --from-definitions or --snapshot", config.EnvURI)
+	case sources > 1:
+		return fmt.Errorf("--uri, --from-definitions and --snapshot are mutually exclusive")
+	}
+	return nil
+}
+
+// newClient creates a management API client for the broker configured in the options.
+func newClient(opts cli.Options) (*rabbitmq.Client, error) {
+	httpClient, err := rabbitmq.NewHTTPClient(rabbitmq.TLSOptions{
+		CACertFile:         opts.CACertFile,
+		ClientCertFile:     opts.ClientCertFile,
+		ClientKeyFile:      opts.ClientKeyFile,
+		InsecureSkipVerify: opts.InsecureSkipVerify,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("TLS configuration error: %w", err)
+	}
+
+	client, err := rabbitmq.NewClient(opts.URI, httpClient)
+	if err != nil {
+		return nil, fmt.Errorf("connection error to broker : %w", err)
+	}
+	if auth := authenticator(opts, httpClient); auth != nil {
+		client.Auth = auth
+	} else if opts.Username != "" || opts.Password != "" {
+		client.Auth = basicAuth(client.Auth, opts)
+	}
+	client.Timeout = opts.Timeout
+	client.Retry.MaxRetries = opts.MaxRetries
+	return client, nil
+}
+
+// authenticator returns the authenticator selected by the token options, or nil
+// to keep the basic auth credentials of the URI.
+//
+// The token endpoint is reached with the same TLS settings as the broker.
+func authenticator(opts cli.Options, httpClient *http.Client) rabbitmq.Authenticator {
+	switch {
+	case opts.Token != "":
+		return rabbitmq.BearerToken(opts.Token)
+	case opts.TokenFile != "":
+		return rabbitmq.TokenFile{Path: opts.TokenFile}
+	case opts.OAuthTokenURL != "":
+		return &rabbitmq.ClientCredentials{
+			TokenURL:     opts.OAuthTokenURL,
+			ClientID:     opts.OAuthClientID,
+			ClientSecret: opts.OAuthClientSecret,
+			Scopes:       opts.OAuthScopes,
+			Http:         httpClient,
+		}
+	}
+	return nil
+}
+
+// basicAuth overrides the credentials of the URI with the user and password options, if set.
+func basicAuth(fromURI rabbitmq.Authenticator, opts cli.Options) rabbitmq.BasicAuth {
+	auth, _ := fromURI.(rabbitmq.BasicAuth)
+	if opts.Username != "" {
+		auth.Username = opts.Username
+	}
+	if opts.Password != "" {
+		auth.Password = opts.Password
+	}
+	return auth
+}
+
+// loadTopology returns the topology described by the options: read from a
+// snapshot or a definitions export when one is given, fetched from the
+// management API otherwise.
+func loadTopology(ctx context.Context, opts cli.Options) (*rabbitmq.Topology, error) {
+	switch {
+	case opts.SnapshotFile != "":
+		snap, err := snapshot.Load(opts.SnapshotFile)
+		if err != nil {
+			return nil, fmt.Errorf("snapshot error: %w", err)
+		}
+		return snap.Topology, nil
+	case opts.DefinitionsFile != "":
+		topology, err := rabbitmq.LoadDefinitions(opts.DefinitionsFile)
+		if err != nil {
+			return nil, fmt.Errorf("definitions error: %w", err)
+		}
+		return topology, nil
+	}
+
+	client, err := newClient(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	topology, err := client.FetchTopologyContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("fetch error: %w", err)
+	}
+	return topology, nil
+}
@@ -1,12 +1,35 @@
 package cli
 
+import "time"
 
 // Options contains command line arguments passed to generate or tui commands.
 type Options struct {
-	URI            string
-	GroupBy        string
-	FilterVhost    string
-	FilterExchange string
-	OutFile        string
-	ShowMsgStats   bool
-}
\ No newline at end of file
+	Context            string
+	URI                string
+	Username           string
+	Password           string
+	PasswordFile       string
+	DefinitionsFile    string
+	SnapshotFile       string
+	GroupBy            string
+	FilterVhost        string
+	FilterExchange     string
+	OutFile            string
+	Title              string
+	Format             string
+	ShowMsgStats       bool
+	TraceRoute         string
+	RefreshInterval    time.Duration
+	Timeout            time.Duration
+	MaxRetries         int
+	CACertFile         string
+	ClientCertFile     string
+	ClientKeyFile      string
+	InsecureSkipVerify bool
+	Token              string
+	TokenFile          string
+	OAuthTokenURL      string
+	OAuthClientID      string
+	OAuthClientSecret  string
+	OAuthScopes        []string
+}
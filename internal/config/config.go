@@ -0,0 +1,364 @@
+// Package config resolves broker connection settings from command line flags,
+// environment variables and the AIM-Q configuration file, so credentials do not
+// have to be passed inside --uri where they leak to shell history and ps.
+//
+// The configuration file may list named brokers, called contexts, and select
+// one of them as the current context, like kubectl contexts.
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Patrick-Ivann/AIM-Q/internal/cli"
+	"gopkg.in/yaml.v3"
+)
+
+// Environment variables read by Resolve and DefaultPath.
+const (
+	EnvURI      = "AIMQ_URI"      // Management API URI
+	EnvUsername = "AIMQ_USERNAME" // Basic auth user name
+	EnvPassword = "AIMQ_PASSWORD" // Basic auth password
+	EnvContext  = "AIMQ_CONTEXT"  // Name of the context to use
+	EnvConfig   = "AIMQ_CONFIG"   // Path of the configuration file
+)
+
+// Config is the content of the configuration file:
+//
+//	current-context: prod-eu
+//	contexts:
+//	  - name: prod-eu
+//	    uri: https://rabbitmq.eu.example.com:15671
+//	    username: monitoring
+//	    password-file: ~/.secrets/rabbitmq-eu   # or password: ...
+//	    ca-cert: ~/.certs/internal-ca.pem
+//	    filter-vhost: orders
+//	  - name: staging
+//	    uri: http://rabbitmq.staging:15672
+//	    token-file: /var/run/secrets/rabbitmq/token
+//
+// Broker settings may also be given at the top level, without contexts; they
+// apply when no context is selected.
+type Config struct {
+	Broker         `yaml:",inline"`
+	CurrentContext string    `yaml:"current-context"`
+	Contexts       []Context `yaml:"contexts"`
+}
+
+// Context is a named broker.
+type Context struct {
+	Name   string `yaml:"name"`
+	Broker `yaml:",inline"`
+}
+
+// Broker holds the settings needed to connect to a broker.
+//
+// Relative file paths are relative to the configuration file and may start with "~/".
+type Broker struct {
+	URI                string   `yaml:"uri"`
+	Username           string   `yaml:"username"`
+	Password           string   `yaml:"password"`
+	PasswordFile       string   `yaml:"password-file"`
+	Token              string   `yaml:"token"`
+	TokenFile          string   `yaml:"token-file"`
+	OAuthTokenURL      string   `yaml:"oauth-token-url"`
+	OAuthClientID      string   `yaml:"oauth-client-id"`
+	OAuthClientSecret  string   `yaml:"oauth-client-secret"`
+	OAuthScopes        []string `yaml:"oauth-scopes"`
+	CACert             string   `yaml:"ca-cert"`
+	ClientCert         string   `yaml:"client-cert"`
+	ClientKey          string   `yaml:"client-key"`
+	InsecureSkipVerify bool     `yaml:"insecure-skip-verify"`
+	FilterVhost        string   `yaml:"filter-vhost"` // Default of --filter-vhost
+}
+
+// DefaultPath returns the path of the configuration file: $AIMQ_CONFIG if set,
+// aimq/config.yaml in the user configuration directory otherwise
+// (~/.config/aimq/config.yaml on Linux).
+func DefaultPath() (string, error) {
+	if path := os.Getenv(EnvConfig); path != "" {
+		return path, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("locating configuration directory: %w", err)
+	}
+	return filepath.Join(dir, "aimq", "config.yaml"), nil
+}
+
+// Load reads the configuration file at path.
+//
+// A missing file yields an empty configuration unless required is set, so the
+// default file is optional while a file given explicitly must exist.
+func Load(path string, required bool) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) && !required {
+		return &Config{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading config: %w", err)
+	}
+
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("decoding config %s: %w", path, err)
+	}
+	if err := cfg.validate(); err != nil {
+		return nil, fmt.Errorf("config %s: %w", path, err)
+	}
+
+	dir := filepath.Dir(path)
+	cfg.resolvePaths(dir)
+	for i := range cfg.Contexts {
+		cfg.Contexts[i].resolvePaths(dir)
+	}
+	return &cfg, nil
+}
+
+// validate checks that context names are unique and passwords are given once.
+func (c *Config) validate() error {
+	if err := c.Broker.validate(); err != nil {
+		return err
+	}
+	seen := make(map[string]bool, len(c.Contexts))
+	for _, ctx := range c.Contexts {
+		switch {
+		case ctx.Name == "":
+			return errors.New("context without a name")
+		case seen[ctx.Name]:
+			return fmt.Errorf("duplicate context %q", ctx.Name)
+		}
+		seen[ctx.Name] = true
+		if err := ctx.Broker.validate(); err != nil {
+			return fmt.Errorf("context %q: %w", ctx.Name, err)
+		}
+	}
+	return nil
+}
+
+// validate checks that the password is given at most once.
+func (b *Broker) validate() error {
+	if b.Password != "" && b.PasswordFile != "" {
+		return errors.New("password and password-file are mutually exclusive")
+	}
+	return nil
+}
+
+// resolvePaths makes the file paths of the broker absolute.
+func (b *Broker) resolvePaths(dir string) {
+	for _, path := range []*string{&b.PasswordFile, &b.TokenFile, &b.CACert, &b.ClientCert, &b.ClientKey} {
+		*path = resolvePath(*path, dir)
+	}
+}
+
+// resolvePath expands a leading "~/" to the home directory and makes relative paths relative to dir.
+func resolvePath(path, dir string) string {
+	if home, err := os.UserHomeDir(); err == nil && strings.HasPrefix(path, "~/") {
+		return filepath.Join(home, path[2:])
+	}
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(dir, path)
+}
+
+// Context returns the context with the given name.
+func (c *Config) Context(name string) (*Context, error) {
+	for i := range c.Contexts {
+		if c.Contexts[i].Name == name {
+			return &c.Contexts[i], nil
+		}
+	}
+	return nil, fmt.Errorf("unknown context %q", name)
+}
+
+// Select returns the broker settings of the named context, or the top-level
+// settings if name is empty.
+func (c *Config) Select(name string) (Broker, error) {
+	if name == "" {
+		return c.Broker, nil
+	}
+	ctx, err := c.Context(name)
+	if err != nil {
+		return Broker{}, err
+	}
+	return ctx.Broker, nil
+}
+
+// Resolve completes the connection settings of opts, which hold the command line
+// flags, from the environment and the configuration file.
+//
+// Each setting is taken from the first source defining it, in order: flags,
+// environment variables (read with getenv), configuration file. The settings of
+// the configuration file are those of the context named by opts.Context,
+// $AIMQ_CONTEXT or current-context, in this order. A context selected by the
+// first two was explicitly requested, so its URI shadows $AIMQ_URI and its
+// credentials take precedence over $AIMQ_USERNAME and $AIMQ_PASSWORD; these are
+// still used when the context does not set a user name or a password.
+//
+// The password and the password file are a single setting, so AIMQ_PASSWORD
+// overrides a password-file of the configuration file and --password-file
+// overrides both. The password file is then read into opts.Password.
+//
+// Credentials embedded in the --uri flag are a flag setting too: when present,
+// the user name and password are not taken from the environment or the
+// configuration file, only from --username, --password and --password-file.
+func Resolve(opts cli.Options, cfg *Config, getenv func(string) string) (cli.Options, error) {
+	uriCredentials := hasUserinfo(opts.URI)
+	explicit := firstNonEmpty(opts.Context, getenv(EnvContext))
+	opts.Context = firstNonEmpty(explicit, cfg.CurrentContext)
+	broker, err := cfg.Select(opts.Context)
+	if err != nil {
+		return opts, err
+	}
+	if explicit != "" {
+		environ := getenv
+		getenv = func(key string) string {
+			switch {
+			case key == EnvURI,
+				key == EnvUsername && broker.Username != "",
+				key == EnvPassword && (broker.Password != "" || broker.PasswordFile != ""):
+				return ""
+			}
+			return environ(key)
+		}
+	}
+
+	opts.URI = firstNonEmpty(opts.URI, getenv(EnvURI), broker.URI)
+	opts.FilterVhost = firstNonEmpty(opts.FilterVhost, broker.FilterVhost)
+	resolveTLS(&opts, broker)
+	resolveToken(&opts, broker)
+	if uriCredentials {
+		return resolveBasicAuth(opts, Broker{}, func(string) string { return "" })
+	}
+	return resolveBasicAuth(opts, broker, getenv)
+}
+
+// hasUserinfo reports whether uri embeds a user name or password.
+func hasUserinfo(uri string) bool {
+	u, err := url.Parse(uri)
+	return err == nil && u.User != nil
+}
+
+// resolveBasicAuth completes the user name and password and reads the password file.
+func resolveBasicAuth(opts cli.Options, broker Broker, getenv func(string) string) (cli.Options, error) {
+	opts.Username = firstNonEmpty(opts.Username, getenv(EnvUsername), broker.Username)
+
+	switch {
+	case opts.Password != "" || opts.PasswordFile != "":
+	case getenv(EnvPassword) != "":
+		opts.Password = getenv(EnvPassword)
+	default:
+		opts.Password, opts.PasswordFile = broker.Password, broker.PasswordFile
+	}
+
+	if opts.Password == "" && opts.PasswordFile != "" {
+		password, err := readPasswordFile(opts.PasswordFile)
+		if err != nil {
+			return opts, err
+		}
+		opts.Password = password
+	}
+	return opts, nil
+}
+
+// resolveTLS completes the TLS settings.
+func resolveTLS(opts *cli.Options, broker Broker) {
+	opts.CACertFile = firstNonEmpty(opts.CACertFile, broker.CACert)
+	if opts.ClientCertFile == "" {
+		opts.ClientCertFile, opts.ClientKeyFile = broker.ClientCert, broker.ClientKey
+	}
+	opts.InsecureSkipVerify = opts.InsecureSkipVerify || broker.InsecureSkipVerify
+}
+
+// resolveToken takes the token settings of the broker unless a token flag is set,
+// as the token sources are mutually exclusive.
+func resolveToken(opts *cli.Options, broker Broker) {
+	if opts.Token != "" || opts.TokenFile != "" || opts.OAuthTokenURL != "" {
+		return
+	}
+	opts.Token = broker.Token
+	opts.TokenFile = broker.TokenFile
+	opts.OAuthTokenURL = broker.OAuthTokenURL
+	opts.OAuthClientID = broker.OAuthClientID
+	opts.OAuthClientSecret = broker.OAuthClientSecret
+	opts.OAuthScopes = broker.OAuthScopes
+}
+
+// readPasswordFile returns the content of a password file without its trailing newline.
+func readPasswordFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("reading password file: %w", err)
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+// firstNonEmpty returns the first non-empty value.
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// SetCurrentContext makes name the current context of the configuration file at path.
+//
+// Only the current-context key is changed; comments and the content of the rest
+// of the file are preserved, although it is re-indented with two spaces.
+func SetCurrentContext(path, name string) error {
+	cfg, err := Load(path, true)
+	if err != nil {
+		return err
+	}
+	if _, err := cfg.Context(name); err != nil {
+		return err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("reading config: %w", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading config: %w", err)
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("decoding config %s: %w", path, err)
+	}
+
+	setMappingValue(doc.Content[0], "current-context", name)
+	var out bytes.Buffer
+	enc := yaml.NewEncoder(&out)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return fmt.Errorf("encoding config: %w", err)
+	}
+	if err := os.WriteFile(path, out.Bytes(), info.Mode().Perm()); err != nil {
+		return fmt.Errorf("writing config: %w", err)
+	}
+	return nil
+}
+
+// setMappingValue sets key to a string value in a YAML mapping node, adding it first if missing.
+func setMappingValue(mapping *yaml.Node, key, value string) {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			mapping.Content[i+1].SetString(value)
+			return
+		}
+	}
+	var k, v yaml.Node
+	k.SetString(key)
+	v.SetString(value)
+	mapping.Content = append([]*yaml.Node{&k, &v}, mapping.Content...)
+}
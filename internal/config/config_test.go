@@ -0,0 +1,296 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/Patrick-Ivann/AIM-Q/internal/cli"
+	"github.com/Patrick-Ivann/AIM-Q/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// env returns a getenv function backed by a map.
+func env(vars map[string]string) func(string) string {
+	return func(key string) string { return vars[key] }
+}
+
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	path := writeFile(t, dir, "config.yaml", "uri: http://rabbit:15672\nusername: monitoring\npassword-file: secret\n")
+
+	cfg, err := config.Load(path, true)
+	require.NoError(t, err)
+	assert.Equal(t, &config.Config{Broker: config.Broker{
+		URI:          "http://rabbit:15672",
+		Username:     "monitoring",
+		PasswordFile: filepath.Join(dir, "secret"),
+	}}, cfg)
+}
+
+func TestLoad_Missing(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+
+	cfg, err := config.Load(path, false)
+	require.NoError(t, err)
+	assert.Equal(t, &config.Config{}, cfg)
+
+	_, err = config.Load(path, true)
+	assert.ErrorContains(t, err, "reading config")
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := config.Load(writeFile(t, dir, "bad.yaml", "uri: [\n"), true)
+	assert.ErrorContains(t, err, "decoding config")
+
+	_, err = config.Load(writeFile(t, dir, "both.yaml", "password: a\npassword-file: b\n"), true)
+	assert.ErrorContains(t, err, "mutually exclusive")
+}
+
+func TestDefaultPath(t *testing.T) {
+	t.Setenv(config.EnvConfig, "/etc/aimq.yaml")
+	path, err := config.DefaultPath()
+	require.NoError(t, err)
+	assert.Equal(t, "/etc/aimq.yaml", path)
+
+	if runtime.GOOS != "linux" {
+		return
+	}
+	t.Setenv(config.EnvConfig, "")
+	t.Setenv("XDG_CONFIG_HOME", "/home/me/.config")
+	path, err = config.DefaultPath()
+	require.NoError(t, err)
+	assert.Equal(t, "/home/me/.config/aimq/config.yaml", path)
+}
+
+func TestResolve_Precedence(t *testing.T) {
+	dir := t.TempDir()
+	flagPasswordFile := writeFile(t, dir, "flag-password", "from-flag-file\n")
+	cfgPasswordFile := writeFile(t, dir, "cfg-password", "from-config-file\n")
+	cfg := &config.Config{Broker: config.Broker{
+		URI: "http://config:15672", Username: "config-user", PasswordFile: cfgPasswordFile,
+	}}
+
+	tests := []struct {
+		name  string
+		flags cli.Options
+		env   map[string]string
+		want  cli.Options
+	}{
+		{
+			name: "config only",
+			want: cli.Options{URI: "http://config:15672", Username: "config-user",
+				Password: "from-config-file", PasswordFile: cfgPasswordFile},
+		},
+		{
+			name: "environment overrides config",
+			env: map[string]string{
+				config.EnvURI: "http://env:15672", config.EnvUsername: "env-user", config.EnvPassword: "from-env",
+			},
+			want: cli.Options{URI: "http://env:15672", Username: "env-user", Password: "from-env"},
+		},
+		{
+			name:  "flags override environment",
+			flags: cli.Options{URI: "http://flag:15672", Username: "flag-user", PasswordFile: flagPasswordFile},
+			env: map[string]string{
+				config.EnvURI: "http://env:15672", config.EnvUsername: "env-user", config.EnvPassword: "from-env",
+			},
+			want: cli.Options{URI: "http://flag:15672", Username: "flag-user",
+				Password: "from-flag-file", PasswordFile: flagPasswordFile},
+		},
+		{
+			name:  "sources are merged per setting",
+			flags: cli.Options{URI: "http://flag:15672"},
+			env:   map[string]string{config.EnvUsername: "env-user"},
+			want: cli.Options{URI: "http://flag:15672", Username: "env-user",
+				Password: "from-config-file", PasswordFile: cfgPasswordFile},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := config.Resolve(tt.flags, cfg, env(tt.env))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestResolve_URICredentials(t *testing.T) {
+	cfg := &config.Config{Broker: config.Broker{Username: "config-user", Password: "config-password"}}
+	vars := map[string]string{config.EnvUsername: "env-user", config.EnvPassword: "env-password"}
+
+	t.Run("environment and config do not replace them", func(t *testing.T) {
+		got, err := config.Resolve(cli.Options{URI: "http://alice:pw@flag:15672"}, cfg, env(vars))
+		require.NoError(t, err)
+		assert.Equal(t, cli.Options{URI: "http://alice:pw@flag:15672"}, got)
+	})
+
+	t.Run("flags still override them", func(t *testing.T) {
+		got, err := config.Resolve(cli.Options{URI: "http://alice:pw@flag:15672", Username: "flag-user"}, cfg, env(vars))
+		require.NoError(t, err)
+		assert.Equal(t, cli.Options{URI: "http://alice:pw@flag:15672", Username: "flag-user"}, got)
+	})
+
+	t.Run("only when given by the flag", func(t *testing.T) {
+		got, err := config.Resolve(cli.Options{}, cfg, env(map[string]string{
+			config.EnvURI: "http://alice:pw@env:15672", config.EnvUsername: "env-user",
+		}))
+		require.NoError(t, err)
+		assert.Equal(t, "env-user", got.Username)
+	})
+}
+
+func TestResolve_MissingPasswordFile(t *testing.T) {
+	opts := cli.Options{PasswordFile: filepath.Join(t.TempDir(), "missing")}
+
+	_, err := config.Resolve(opts, &config.Config{}, env(nil))
+	assert.ErrorContains(t, err, "reading password file")
+}
+
+const contextsConfig = `# Brokers of the team
+current-context: staging
+contexts:
+  - name: staging
+    uri: http://rabbitmq.staging:15672
+    token-file: token
+  - name: prod-eu # main cluster
+    uri: https://rabbitmq.eu:15671
+    username: monitoring
+    password: s3cret
+    ca-cert: ~/ca.pem
+    filter-vhost: orders
+`
+
+func TestResolve_Contexts(t *testing.T) {
+	dir := t.TempDir()
+	cfg, err := config.Load(writeFile(t, dir, "config.yaml", contextsConfig), true)
+	require.NoError(t, err)
+	home, err := os.UserHomeDir()
+	require.NoError(t, err)
+
+	t.Run("current context", func(t *testing.T) {
+		got, err := config.Resolve(cli.Options{}, cfg, env(nil))
+		require.NoError(t, err)
+		assert.Equal(t, cli.Options{
+			Context:   "staging",
+			URI:       "http://rabbitmq.staging:15672",
+			TokenFile: filepath.Join(dir, "token"),
+		}, got)
+	})
+
+	t.Run("selected context", func(t *testing.T) {
+		got, err := config.Resolve(cli.Options{Context: "prod-eu"}, cfg, env(nil))
+		require.NoError(t, err)
+		assert.Equal(t, cli.Options{
+			Context:     "prod-eu",
+			URI:         "https://rabbitmq.eu:15671",
+			Username:    "monitoring",
+			Password:    "s3cret",
+			CACertFile:  filepath.Join(home, "ca.pem"),
+			FilterVhost: "orders",
+		}, got)
+	})
+
+	t.Run("flags override the context", func(t *testing.T) {
+		got, err := config.Resolve(cli.Options{Context: "prod-eu", FilterVhost: "billing"}, cfg, env(nil))
+		require.NoError(t, err)
+		assert.Equal(t, "billing", got.FilterVhost)
+	})
+
+	t.Run("environment overrides the current context", func(t *testing.T) {
+		got, err := config.Resolve(cli.Options{}, cfg, env(map[string]string{config.EnvURI: "http://env:15672"}))
+		require.NoError(t, err)
+		assert.Equal(t, "http://env:15672", got.URI)
+	})
+
+	t.Run("explicit context overrides the environment", func(t *testing.T) {
+		got, err := config.Resolve(cli.Options{}, cfg, env(map[string]string{
+			config.EnvContext: "prod-eu", config.EnvURI: "http://env:15672",
+		}))
+		require.NoError(t, err)
+		assert.Equal(t, "prod-eu", got.Context)
+		assert.Equal(t, "https://rabbitmq.eu:15671", got.URI)
+	})
+
+	t.Run("explicit context overrides the environment credentials", func(t *testing.T) {
+		got, err := config.Resolve(cli.Options{Context: "prod-eu"}, cfg, env(map[string]string{
+			config.EnvUsername: "env-user", config.EnvPassword: "from-env",
+		}))
+		require.NoError(t, err)
+		assert.Equal(t, "monitoring", got.Username)
+		assert.Equal(t, "s3cret", got.Password)
+	})
+
+	t.Run("environment credentials complete an explicit context", func(t *testing.T) {
+		got, err := config.Resolve(cli.Options{}, cfg, env(map[string]string{
+			config.EnvContext: "staging", config.EnvURI: "http://env:15672",
+			config.EnvUsername: "env-user", config.EnvPassword: "from-env",
+		}))
+		require.NoError(t, err)
+		assert.Equal(t, "http://rabbitmq.staging:15672", got.URI)
+		assert.Equal(t, "env-user", got.Username)
+		assert.Equal(t, "from-env", got.Password)
+	})
+
+	t.Run("unknown context", func(t *testing.T) {
+		_, err := config.Resolve(cli.Options{Context: "prod-us"}, cfg, env(nil))
+		assert.ErrorContains(t, err, `unknown context "prod-us"`)
+	})
+}
+
+func TestLoad_InvalidContexts(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := map[string]string{
+		"duplicate": "contexts:\n  - name: a\n  - name: a\n",
+		"unnamed":   "contexts:\n  - uri: http://a\n",
+		"password":  "contexts:\n  - name: a\n    password: x\n    password-file: y\n",
+	}
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := config.Load(writeFile(t, dir, name+".yaml", content), true)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestSetCurrentContext(t *testing.T) {
+	path := writeFile(t, t.TempDir(), "config.yaml", contextsConfig)
+
+	require.NoError(t, config.SetCurrentContext(path, "prod-eu"))
+
+	cfg, err := config.Load(path, true)
+	require.NoError(t, err)
+	assert.Equal(t, "prod-eu", cfg.CurrentContext)
+	assert.Len(t, cfg.Contexts, 2)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "# Brokers of the team")
+	assert.Contains(t, string(data), "# main cluster")
+
+	assert.ErrorContains(t, config.SetCurrentContext(path, "prod-us"), "unknown context")
+}
+
+func TestSetCurrentContext_AddsKey(t *testing.T) {
+	path := writeFile(t, t.TempDir(), "config.yaml", "contexts:\n  - name: local\n    uri: http://localhost:15672\n")
+
+	require.NoError(t, config.SetCurrentContext(path, "local"))
+
+	cfg, err := config.Load(path, true)
+	require.NoError(t, err)
+	assert.Equal(t, "local", cfg.CurrentContext)
+	assert.Equal(t, "http://localhost:15672", cfg.Contexts[0].URI)
+}
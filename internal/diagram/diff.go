@@ -0,0 +1,149 @@
+package diagram
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Patrick-Ivann/AIM-Q/internal/diff"
+	"github.com/Patrick-Ivann/AIM-Q/internal/rabbitmq"
+)
+
+// diffNodeColors maps a change status to the background of exchange and queue rectangles.
+var diffNodeColors = map[diff.Status]string{
+	diff.Added:     "#A5D6A7",
+	diff.Removed:   "#EF9A9A",
+	diff.Changed:   "#FFF59D",
+	diff.Unchanged: "#white",
+}
+
+// diffArrows maps a change status to the PlantUML arrow used for bindings.
+var diffArrows = map[diff.Status]string{
+	diff.Added:     "-[#2E7D32,bold]->",
+	diff.Removed:   "-[#C62828,dashed]->",
+	diff.Changed:   "-->",
+	diff.Unchanged: "-->",
+}
+
+// GenerateDiff produces PlantUML source showing the union of two topologies,
+// with added objects in green, removed ones in red and changed ones in yellow.
+//
+// Objects are grouped by vhost; changed fields are listed in the object label.
+func GenerateDiff(result *diff.Result) string {
+	var sb strings.Builder
+
+	sb.WriteString("@startuml topology diff\n")
+	sb.WriteString("skinparam shadowing false\n\n")
+
+	for _, vhost := range diffVhosts(result) {
+		sb.WriteString(fmt.Sprintf("package \"%s\" {\n", vhost))
+		defined := writeDiffNodes(&sb, result, vhost)
+		writeDiffBindings(&sb, result.Bindings, vhost, defined)
+		sb.WriteString("}\n")
+	}
+
+	sb.WriteString("legend right\n")
+	sb.WriteString("  <back:#A5D6A7> added </back>\n")
+	sb.WriteString("  <back:#EF9A9A> removed </back>\n")
+	sb.WriteString("  <back:#FFF59D> changed </back>\n")
+	sb.WriteString("endlegend\n")
+	sb.WriteString("@enduml\n")
+	return sb.String()
+}
+
+// writeDiffNodes emits the exchanges and queues of a vhost and returns the IDs of defined exchanges.
+func writeDiffNodes(sb *strings.Builder, result *diff.Result, vhost string) map[string]struct{} {
+	defined := make(map[string]struct{})
+
+	for _, c := range result.Exchanges {
+		ex := current(c)
+		if ex.Vhost != vhost {
+			continue
+		}
+		exID := sanitize("ex_" + ex.Vhost + "_" + ex.Name)
+		defined[exID] = struct{}{}
+		label := fmt.Sprintf("%s exchange: %s\\n(type=%s)%s", rabbitmq.ExchangeIcon(ex.Type), ex.Name, ex.Type, formatFieldChanges(c.Fields))
+		sb.WriteString(fmt.Sprintf("rectangle \"%s\" as %s %s\n", label, exID, diffNodeColors[c.Status]))
+	}
+
+	for _, c := range result.Queues {
+		q := current(c)
+		if q.Vhost != vhost {
+			continue
+		}
+		label := fmt.Sprintf("📦 queue: %s%s", q.Name, formatFieldChanges(c.Fields))
+		sb.WriteString(fmt.Sprintf("rectangle \"%s\" as %s %s\n",
+			label, sanitize("qu_"+q.Vhost+"_"+q.Name), diffNodeColors[c.Status]))
+	}
+	return defined
+}
+
+// writeDiffBindings emits binding arrows of a vhost, colored by change status.
+func writeDiffBindings(
+	sb *strings.Builder, bindings []diff.Change[rabbitmq.Binding], vhost string, defined map[string]struct{},
+) {
+	for _, c := range bindings {
+		b := current(c)
+		if b.Vhost != vhost {
+			continue
+		}
+
+		source := b.Source
+		if source == "" {
+			source = "default"
+		}
+		src := sanitize("ex_" + b.Vhost + "_" + source)
+		if _, exists := defined[src]; !exists {
+			defined[src] = struct{}{}
+			sb.WriteString(fmt.Sprintf("rectangle \"exchange: %s\" as %s #white\n", source, src))
+		}
+
+		dst := sanitize("ex_" + b.Vhost + "_" + b.Destination)
+		if b.DestType == "queue" {
+			dst = sanitize("qu_" + b.Vhost + "_" + b.Destination)
+		}
+
+		label := ""
+		if b.RoutingKey != "" {
+			label = fmt.Sprintf(" : \"%s\"", escapeLabel(b.RoutingKey))
+		}
+		sb.WriteString(fmt.Sprintf("%s %s %s%s\n", src, diffArrows[c.Status], dst, label))
+	}
+}
+
+// current returns the newest version of a changed object: the new one, or the old one if removed.
+func current[T any](c diff.Change[T]) T {
+	if c.New != nil {
+		return *c.New
+	}
+	return *c.Old
+}
+
+// formatFieldChanges renders changed fields as extra label lines.
+func formatFieldChanges(fields []diff.FieldChange) string {
+	var sb strings.Builder
+	for _, f := range fields {
+		sb.WriteString(escapeLabel(fmt.Sprintf("\n~ %s: %s → %s", f.Field, diff.FormatValue(f.Old), diff.FormatValue(f.New))))
+	}
+	return sb.String()
+}
+
+// diffVhosts returns the sorted vhosts of every object in the diff.
+func diffVhosts(result *diff.Result) []string {
+	set := make(map[string]struct{})
+	for _, c := range result.Exchanges {
+		set[current(c).Vhost] = struct{}{}
+	}
+	for _, c := range result.Queues {
+		set[current(c).Vhost] = struct{}{}
+	}
+	for _, c := range result.Bindings {
+		set[current(c).Vhost] = struct{}{}
+	}
+	vhosts := make([]string, 0, len(set))
+	for v := range set {
+		vhosts = append(vhosts, v)
+	}
+	sort.Strings(vhosts)
+	return vhosts
+}
@@ -0,0 +1,30 @@
+package diagram_test
+
+import (
+	"testing"
+
+	"github.com/Patrick-Ivann/AIM-Q/internal/diagram"
+	"github.com/Patrick-Ivann/AIM-Q/internal/diff"
+	"github.com/Patrick-Ivann/AIM-Q/internal/rabbitmq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateDiff(t *testing.T) {
+	before := sampleTopology()
+	after := sampleTopology()
+	after.Exchanges[0].Durable = true
+	after.Exchanges[0].Arguments = map[string]any{"alternate-exchange": "unrouted"}
+	after.Queues = append(after.Queues, rabbitmq.Queue{Name: "order.shipped", Vhost: "/"})
+	after.Bindings[0].RoutingKey = "order.#"
+
+	out := diagram.GenerateDiff(diff.Compare(before, after))
+
+	assert.Contains(t, out, "@startuml topology diff\n")
+	assert.Contains(t, out, "rectangle \"🧩 exchange: orders\\n(type=topic)\\n~ durable: false → true\\n~ arguments.alternate-exchange: (none) → unrouted\" as ex___orders #FFF59D\n")
+	assert.Contains(t, out, "rectangle \"📦 queue: order.shipped\" as qu___order_shipped #A5D6A7\n")
+	assert.Contains(t, out, "rectangle \"📦 queue: invoices\" as qu_prod_invoices #white\n")
+	assert.Contains(t, out, "ex___orders -[#2E7D32,bold]-> qu___order_created : \"order.#\"\n")
+	assert.Contains(t, out, "ex___orders -[#C62828,dashed]-> qu___order_created : \"order.\\\"created\\\"\"\n")
+	assert.Contains(t, out, "ex_prod_default --> qu_prod_invoices : \"invoices\"\n")
+	assert.Contains(t, out, "legend right\n")
+}
@@ -0,0 +1,163 @@
+package diagram
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Patrick-Ivann/AIM-Q/internal/cli"
+	"github.com/Patrick-Ivann/AIM-Q/internal/rabbitmq"
+)
+
+// GenerateDOT produces Graphviz DOT source visualizing the given RabbitMQ topology.
+//
+// Groups (vhost or type) are rendered as clusters, nested in one cluster per
+// RabbitMQ cluster for merged multi-cluster topologies. Exchanges are filled with
+// their type color and binding routing keys become edge labels.
+func GenerateDOT(topology *rabbitmq.Topology, opts cli.Options) string {
+	var sb strings.Builder
+
+	sb.WriteString("digraph topology {\n")
+	sb.WriteString("  rankdir=LR;\n")
+	if t := title(opts); t != "" {
+		sb.WriteString(fmt.Sprintf("  label=%s;\n  labelloc=t;\n", dotQuote(t)))
+	}
+	sb.WriteString("  node [fontname=\"Helvetica\", shape=box];\n")
+	sb.WriteString("  edge [fontname=\"Helvetica\", fontsize=10];\n\n")
+
+	definedExchanges := make(map[string]struct{})
+
+	for i, cluster := range topology.Clusters() {
+		writeDOTBrokerCluster(&sb, topology.InCluster(cluster), opts, i, cluster, definedExchanges)
+	}
+
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+// writeDOTBrokerCluster emits the groups of one RabbitMQ cluster, wrapped in an
+// outer subgraph cluster when the topology merges several RabbitMQ clusters.
+func writeDOTBrokerCluster(
+	sb *strings.Builder, topology *rabbitmq.Topology, opts cli.Options,
+	index int, cluster string, definedExchanges map[string]struct{},
+) {
+	prefix := "cluster_"
+	if cluster != "" {
+		prefix = fmt.Sprintf("cluster_%d_", index)
+		sb.WriteString(fmt.Sprintf("  subgraph cluster_%d {\n", index))
+		sb.WriteString(fmt.Sprintf("    label=%s;\n", dotQuote(cluster)))
+		sb.WriteString("    style=bold;\n")
+	}
+
+	for i, group := range determineGroups(topology, opts) {
+		writeDOTCluster(sb, topology, opts, fmt.Sprintf("%s%d", prefix, i), group, definedExchanges)
+	}
+
+	if cluster != "" {
+		sb.WriteString("  }\n")
+	}
+}
+
+// writeDOTCluster emits one subgraph cluster holding the group's nodes and edges.
+func writeDOTCluster(
+	sb *strings.Builder, topology *rabbitmq.Topology, opts cli.Options,
+	name string, group string, definedExchanges map[string]struct{},
+) {
+	sb.WriteString(fmt.Sprintf("  subgraph %s {\n", name))
+	sb.WriteString(fmt.Sprintf("    label=%s;\n", dotQuote(group)))
+	sb.WriteString("    style=rounded;\n")
+
+	writeDOTExchanges(sb, topology.Exchanges, opts, group, definedExchanges)
+	writeDOTQueues(sb, topology.Queues, opts, group)
+	writeDOTBindings(sb, topology.Bindings, opts, group, definedExchanges)
+	writeDOTConsumers(sb, topology.Consumers, opts, group)
+	sb.WriteString("  }\n")
+}
+
+// writeDOTExchanges emits filled box nodes for exchanges belonging to the group.
+func writeDOTExchanges(
+	sb *strings.Builder, exchanges []rabbitmq.Exchange, opts cli.Options,
+	group string, definedExchanges map[string]struct{},
+) {
+	for _, ex := range exchanges {
+		if !matchesGroup(opts, ex.Vhost, ex.Type, group) {
+			continue
+		}
+		exID := nodeID("ex", ex.Cluster, ex.Vhost, ex.Name)
+		definedExchanges[exID] = struct{}{}
+		label := fmt.Sprintf("exchange: %s\n(type=%s)", ex.Name, ex.Type)
+		sb.WriteString(fmt.Sprintf("    %s [label=%s, style=filled, fillcolor=\"#%s\"];\n",
+			dotQuote(exID), dotQuote(label), color(ex.Type)))
+	}
+}
+
+// writeDOTQueues emits rounded box nodes for queues belonging to the group.
+func writeDOTQueues(sb *strings.Builder, queues []rabbitmq.Queue, opts cli.Options, group string) {
+	for _, q := range queues {
+		if !matchesGroup(opts, q.Vhost, "", group) {
+			continue
+		}
+		label := "queue: " + q.Name
+		if opts.ShowMsgStats {
+			label += strings.ReplaceAll(formatMsgStats(q), "\\n", "\n")
+		}
+		sb.WriteString(fmt.Sprintf("    %s [label=%s, style=rounded];\n",
+			dotQuote(nodeID("qu", q.Cluster, q.Vhost, q.Name)), dotQuote(label)))
+	}
+}
+
+// writeDOTBindings emits DOT edges for the group's bindings, labelled with their routing key.
+func writeDOTBindings(
+	sb *strings.Builder, bindings []rabbitmq.Binding, opts cli.Options,
+	group string, definedExchanges map[string]struct{},
+) {
+	for _, b := range bindings {
+		if !matchesGroup(opts, b.Vhost, "", group) {
+			continue
+		}
+
+		// Ensure exchange source is always rendered: special-case for default ("")
+		source := b.Source
+		if source == "" {
+			source = "default"
+		}
+		src := nodeID("ex", b.Cluster, b.Vhost, source)
+		if _, exists := definedExchanges[src]; !exists {
+			definedExchanges[src] = struct{}{}
+			sb.WriteString(fmt.Sprintf("    %s [label=%s, style=filled, fillcolor=%s];\n",
+				dotQuote(src), dotQuote("exchange: default\n(type=direct)"), dotQuote(vhostColor(b.Vhost))))
+		}
+
+		dst := nodeID("ex", b.Cluster, b.Vhost, b.Destination)
+		if b.DestType == "queue" {
+			dst = nodeID("qu", b.Cluster, b.Vhost, b.Destination)
+		}
+
+		attrs := ""
+		if b.RoutingKey != "" {
+			attrs = fmt.Sprintf(" [label=%s]", dotQuote(b.RoutingKey))
+		}
+		sb.WriteString(fmt.Sprintf("    %s -> %s%s;\n", dotQuote(src), dotQuote(dst), attrs))
+	}
+}
+
+// writeDOTConsumers emits ellipse nodes for consumers and dashed delivery edges.
+func writeDOTConsumers(sb *strings.Builder, consumers []rabbitmq.Consumer, opts cli.Options, group string) {
+	for _, c := range consumers {
+		if !matchesGroup(opts, c.Vhost, "", group) {
+			continue
+		}
+		conID := consumerID(c.Cluster, c.ConsumerTag)
+		sb.WriteString(fmt.Sprintf("    %s [label=%s, shape=ellipse];\n",
+			dotQuote(conID), dotQuote("consumer: "+c.ConsumerTag)))
+		sb.WriteString(fmt.Sprintf("    %s -> %s [label=\"delivers\", style=dashed];\n",
+			dotQuote(nodeID("qu", c.Cluster, c.Vhost, c.Queue)), dotQuote(conID)))
+	}
+}
+
+// dotQuote returns s as a double-quoted DOT string, escaping quotes and newlines.
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, "\\", "\\\\")
+	s = strings.ReplaceAll(s, "\"", "\\\"")
+	s = strings.ReplaceAll(s, "\n", "\\n")
+	return "\"" + s + "\""
+}
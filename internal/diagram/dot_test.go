@@ -0,0 +1,69 @@
+package diagram_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Patrick-Ivann/AIM-Q/internal/cli"
+	"github.com/Patrick-Ivann/AIM-Q/internal/diagram"
+	"github.com/Patrick-Ivann/AIM-Q/internal/rabbitmq"
+	"github.com/stretchr/testify/assert"
+)
+
+func sampleTopology() *rabbitmq.Topology {
+	return &rabbitmq.Topology{
+		Exchanges: []rabbitmq.Exchange{
+			{Name: "orders", Vhost: "/", Type: "topic"},
+			{Name: "billing", Vhost: "prod", Type: "fanout"},
+		},
+		Queues: []rabbitmq.Queue{
+			{Name: "order.created", Vhost: "/"},
+			{Name: "invoices", Vhost: "prod"},
+		},
+		Bindings: []rabbitmq.Binding{
+			{Source: "orders", Destination: "order.created", DestType: "queue", Vhost: "/", RoutingKey: "order.\"created\""},
+			{Source: "", Destination: "invoices", DestType: "queue", Vhost: "prod", RoutingKey: "invoices"},
+		},
+		Consumers: []rabbitmq.Consumer{
+			{Queue: "invoices", Vhost: "prod", ConsumerTag: "ctag-1"},
+		},
+	}
+}
+
+func TestGenerateDOT(t *testing.T) {
+	out := diagram.GenerateDOT(sampleTopology(), cli.Options{})
+
+	assert.True(t, strings.HasPrefix(out, "digraph topology {\n"))
+	assert.True(t, strings.HasSuffix(out, "}\n"))
+
+	// One cluster per vhost, in sorted order.
+	assert.Contains(t, out, "subgraph cluster_0 {\n    label=\"/\";")
+	assert.Contains(t, out, "subgraph cluster_1 {\n    label=\"prod\";")
+
+	// Exchange colors follow the exchange type.
+	assert.Contains(t, out, `"ex___orders" [label="exchange: orders\n(type=topic)", style=filled, fillcolor="#4CAF50"];`)
+
+	// Routing keys become escaped edge labels.
+	assert.Contains(t, out, `"ex___orders" -> "qu___order_created" [label="order.\"created\""];`)
+
+	// The default exchange is synthesized for bindings that reference it.
+	assert.Contains(t, out, `"ex_prod_default" [label="exchange: default\n(type=direct)"`)
+	assert.Contains(t, out, `"qu_prod_invoices" -> "cons_ctag_1" [label="delivers", style=dashed];`)
+}
+
+func TestGenerateDOT_GroupByType(t *testing.T) {
+	out := diagram.GenerateDOT(sampleTopology(), cli.Options{GroupBy: "type"})
+
+	assert.Contains(t, out, "label=\"fanout\";")
+	assert.Contains(t, out, "label=\"topic\";")
+	assert.NotContains(t, out, "label=\"prod\";")
+}
+
+func TestGenerateDOT_MessageStats(t *testing.T) {
+	topo := sampleTopology()
+	topo.Queues[0].MessageStats.Messages = 7
+
+	out := diagram.GenerateDOT(topo, cli.Options{ShowMsgStats: true})
+
+	assert.Contains(t, out, `label="queue: order.created\nmessages: 7\nready: 0\nunacked: 0"`)
+}
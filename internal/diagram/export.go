@@ -0,0 +1,97 @@
+package diagram
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/Patrick-Ivann/AIM-Q/internal/rabbitmq"
+	"gopkg.in/yaml.v3"
+)
+
+// ExportSchemaVersion identifies the layout of JSON and YAML exports.
+//
+// It is incremented whenever a field is renamed or removed; adding fields keeps the version.
+const ExportSchemaVersion = 1
+
+// exportDocument is the root object of JSON and YAML exports.
+//
+// The schema is:
+//
+//	schema_version: 1
+//	exchanges: [{name, type, vhost, durable, auto_delete, arguments,
+//	             policy?, effective_policy_definition?}]
+//	queues:    [{name, vhost, durable, auto_delete, exclusive, arguments,
+//	             policy?, operator_policy?, effective_policy_definition?,
+//	             message_stats: {messages, messages_ready, messages_unacknowledged}}]
+//	bindings:  [{source, destination, destination_type, vhost, routing_key, arguments}]
+//	consumers: [{queue, consumer_tag, vhost, channel_details: {pid}}]
+//	policies:  [{name, vhost, pattern, apply-to, definition, priority}]
+//	operator_policies: [{name, vhost, pattern, apply-to, definition, priority}]
+//	shovels:   [{name, vhost, type, state, src_uri, src_queue, src_exchange, src_exchange_key,
+//	             dest_uri, dest_queue, dest_exchange, dest_exchange_key}]
+//	federation_links: [{upstream, vhost, type, exchange, upstream_exchange,
+//	             queue, upstream_queue, uri, status}]
+//
+// Objects of merged multi-cluster topologies also have a cluster field.
+// Passwords in the URIs of shovels and federation links are masked.
+//
+// Lists are sorted (see rabbitmq.Topology.Sort), empty lists are emitted as []
+// and missing arguments as {} so consumers never have to handle nulls.
+type exportDocument struct {
+	SchemaVersion      int `json:"schema_version" yaml:"schema_version"`
+	*rabbitmq.Topology `yaml:",inline"`
+}
+
+// GenerateJSON serializes the topology as an indented JSON export document.
+func GenerateJSON(topology *rabbitmq.Topology) (string, error) {
+	out, err := json.MarshalIndent(newExportDocument(topology), "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("encoding JSON export: %w", err)
+	}
+	return string(out) + "\n", nil
+}
+
+// GenerateYAML serializes the topology as a YAML export document.
+func GenerateYAML(topology *rabbitmq.Topology) (string, error) {
+	out, err := yaml.Marshal(newExportDocument(topology))
+	if err != nil {
+		return "", fmt.Errorf("encoding YAML export: %w", err)
+	}
+	return string(out), nil
+}
+
+// newExportDocument returns a sorted, null-free copy of the topology wrapped in
+// an export document, with the credentials of shovel and federation URIs masked.
+func newExportDocument(topology *rabbitmq.Topology) exportDocument {
+	topology = topology.Redacted()
+	normalized := &rabbitmq.Topology{
+		Exchanges: append([]rabbitmq.Exchange{}, topology.Exchanges...),
+		Queues:    append([]rabbitmq.Queue{}, topology.Queues...),
+		Bindings:  append([]rabbitmq.Binding{}, topology.Bindings...),
+		Consumers: append([]rabbitmq.Consumer{}, topology.Consumers...),
+		Policies:  append([]rabbitmq.Policy{}, topology.Policies...),
+		Shovels:   append([]rabbitmq.Shovel{}, topology.Shovels...),
+
+		OperatorPolicies: append([]rabbitmq.Policy{}, topology.OperatorPolicies...),
+		FederationLinks:  append([]rabbitmq.FederationLink{}, topology.FederationLinks...),
+	}
+	normalized.Sort()
+
+	for i := range normalized.Exchanges {
+		if normalized.Exchanges[i].Arguments == nil {
+			normalized.Exchanges[i].Arguments = map[string]any{}
+		}
+	}
+	for i := range normalized.Queues {
+		if normalized.Queues[i].Arguments == nil {
+			normalized.Queues[i].Arguments = map[string]any{}
+		}
+	}
+	for i := range normalized.Bindings {
+		if normalized.Bindings[i].Arguments == nil {
+			normalized.Bindings[i].Arguments = map[string]any{}
+		}
+	}
+
+	return exportDocument{SchemaVersion: ExportSchemaVersion, Topology: normalized}
+}
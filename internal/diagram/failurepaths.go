@@ -0,0 +1,136 @@
+package diagram
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Patrick-Ivann/AIM-Q/internal/rabbitmq"
+)
+
+// Edge colors of the failure paths, distinct from bindings.
+const (
+	deadLetterColor = "D32F2F"
+	alternateColor  = "616161"
+)
+
+// writeFailurePaths emits dashed edges from queues to their dead-letter exchange
+// and dotted edges from exchanges to their alternate exchange, followed by a
+// legend when there is any.
+//
+// Settings are read from the effective configuration (see rabbitmq.Queue.Effective),
+// so that dead-lettering and alternate exchanges set by policies are drawn too.
+// Target exchanges missing from the diagram are drawn as placeholders, see ensureExchange.
+func writeFailurePaths(
+	sb *strings.Builder, topology *rabbitmq.Topology, definedExchanges, declared map[string]struct{}, tr *trace,
+) {
+	deadLetters := writeDeadLetterEdges(sb, topology.Queues, definedExchanges, declared, tr)
+	alternates := writeAlternateExchangeEdges(sb, topology.Exchanges, definedExchanges, declared, tr)
+	if deadLetters || alternates {
+		writeFailurePathLegend(sb, deadLetters, alternates)
+	}
+}
+
+// writeDeadLetterEdges emits the DLX edges and reports whether there were any.
+func writeDeadLetterEdges(
+	sb *strings.Builder, queues []rabbitmq.Queue, definedExchanges, declared map[string]struct{}, tr *trace,
+) bool {
+	written := false
+	for _, q := range queues {
+		dlx, ok := q.Effective("dead-letter-exchange")
+		if !ok {
+			continue
+		}
+		dst := ensureExchange(sb, q.Cluster, q.Vhost, fmt.Sprint(dlx), definedExchanges, declared, tr)
+		label := "dead-letter"
+		if key, ok := q.Effective("dead-letter-routing-key"); ok {
+			label += fmt.Sprintf(" (%v)", key)
+		}
+		src := nodeID("qu", q.Cluster, q.Vhost, q.Name)
+		arrow := tr.arrow(edgeKey(src, dst, label), deadLetterColor, "dashed")
+		sb.WriteString(fmt.Sprintf("%s %s %s : \"%s\"\n", src, arrow, dst, escapeLabel(label)))
+		written = true
+	}
+	return written
+}
+
+// writeAlternateExchangeEdges emits the AE edges and reports whether there were any.
+func writeAlternateExchangeEdges(
+	sb *strings.Builder, exchanges []rabbitmq.Exchange, definedExchanges, declared map[string]struct{}, tr *trace,
+) bool {
+	written := false
+	for _, ex := range exchanges {
+		ae, ok := ex.Effective("alternate-exchange")
+		if !ok {
+			continue
+		}
+		dst := ensureExchange(sb, ex.Cluster, ex.Vhost, fmt.Sprint(ae), definedExchanges, declared, tr)
+		src := nodeID("ex", ex.Cluster, ex.Vhost, ex.Name)
+		arrow := tr.arrow(edgeKey(src, dst, alternateLabel), alternateColor, "dotted")
+		sb.WriteString(fmt.Sprintf("%s %s %s : \"%s\"\n", src, arrow, dst, alternateLabel))
+		written = true
+	}
+	return written
+}
+
+// ensureExchange returns the alias of an exchange, emitting a placeholder node
+// when the exchange is not part of the diagram. The empty name designates the
+// default exchange, as for bindings, which is always declared.
+//
+// declared holds the aliases returned by declaredNodes for the unfiltered
+// topology, to tell exchanges hidden by the filters from undeclared ones.
+func ensureExchange(
+	sb *strings.Builder, cluster, vhost, name string, definedExchanges, declared map[string]struct{}, tr *trace,
+) string {
+	id := nodeID("ex", cluster, vhost, exchangeName(name))
+	_, isDeclared := declared[id]
+	return ensureNode(sb, "ex", id, exchangeName(name), name == "" || isDeclared, definedExchanges, tr)
+}
+
+// ensureQueue is ensureExchange for queues, whose defined aliases are in definedQueues.
+func ensureQueue(
+	sb *strings.Builder, cluster, vhost, name string, definedQueues, declared map[string]struct{}, tr *trace,
+) string {
+	id := nodeID("qu", cluster, vhost, name)
+	_, isDeclared := declared[id]
+	return ensureNode(sb, "qu", id, name, isDeclared, definedQueues, tr)
+}
+
+// ensureNode emits a placeholder node for the exchange ("ex") or queue ("qu")
+// of alias id unless it is defined, labelled as filtered out if it is declared
+// and as not declared otherwise.
+func ensureNode(sb *strings.Builder, kind, id, name string, declared bool, defined map[string]struct{}, tr *trace) string {
+	if _, exists := defined[id]; exists {
+		return id
+	}
+	defined[id] = struct{}{}
+	label := fmt.Sprintf("❓ %s: %s\\n(not declared)", kindLabel(kind), escapeLabel(name))
+	if declared {
+		label = fmt.Sprintf("%s: %s\\n(filtered out)", kindLabel(kind), escapeLabel(name))
+	}
+	sb.WriteString(fmt.Sprintf("rectangle \"%s\" as %s%s\n", label, id, tr.fill(id, color(""))))
+	return id
+}
+
+// declaredNodes returns the aliases of the exchanges and queues of the topology.
+func declaredNodes(topology *rabbitmq.Topology) map[string]struct{} {
+	nodes := make(map[string]struct{}, len(topology.Exchanges)+len(topology.Queues))
+	for _, ex := range topology.Exchanges {
+		nodes[nodeID("ex", ex.Cluster, ex.Vhost, exchangeName(ex.Name))] = struct{}{}
+	}
+	for _, q := range topology.Queues {
+		nodes[nodeID("qu", q.Cluster, q.Vhost, q.Name)] = struct{}{}
+	}
+	return nodes
+}
+
+// writeFailurePathLegend emits a legend explaining the failure path edges in use.
+func writeFailurePathLegend(sb *strings.Builder, deadLetters, alternates bool) {
+	sb.WriteString("legend right\n")
+	if deadLetters {
+		sb.WriteString("  <color:#" + deadLetterColor + ">- - -></color> dead-letter exchange (DLX)\n")
+	}
+	if alternates {
+		sb.WriteString("  <color:#" + alternateColor + ">. . .></color> alternate exchange (AE)\n")
+	}
+	sb.WriteString("endlegend\n")
+}
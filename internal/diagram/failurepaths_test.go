@@ -0,0 +1,63 @@
+package diagram_test
+
+import (
+	"testing"
+
+	"github.com/Patrick-Ivann/AIM-Q/internal/cli"
+	"github.com/Patrick-Ivann/AIM-Q/internal/diagram"
+	"github.com/Patrick-Ivann/AIM-Q/internal/rabbitmq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerate_DeadLetterEdges(t *testing.T) {
+	topo := &rabbitmq.Topology{
+		Exchanges: []rabbitmq.Exchange{{Name: "orders.dlx", Vhost: "/", Type: "fanout"}},
+		Queues: []rabbitmq.Queue{
+			{Name: "orders", Vhost: "/", Arguments: map[string]any{
+				"x-dead-letter-exchange":    "orders.dlx",
+				"x-dead-letter-routing-key": "failed",
+			}},
+			{Name: "jobs", Vhost: "/", Policy: "dlx", EffectiveDefinition: map[string]any{"dead-letter-exchange": "missing"}},
+		},
+	}
+
+	out := diagram.Generate(topo, cli.Options{})
+	assert.Contains(t, out, "qu___orders -[#D32F2F,dashed]-> ex___orders_dlx : \"dead-letter (failed)\"\n")
+	assert.Contains(t, out, "rectangle \"❓ exchange: missing\\n(not declared)\" as ex___missing #BBBBBB\n")
+	assert.Contains(t, out, "qu___jobs -[#D32F2F,dashed]-> ex___missing : \"dead-letter\"\n", "policies define dead-lettering too")
+	assert.Contains(t, out, "legend right\n  <color:#D32F2F>- - -></color> dead-letter exchange (DLX)\nendlegend\n")
+	assert.NotContains(t, out, "rectangle \"❓ exchange: orders.dlx", "declared exchanges are not redefined")
+}
+
+func TestGenerate_DeadLetterEdgeToFilteredExchange(t *testing.T) {
+	topo := &rabbitmq.Topology{
+		Exchanges: []rabbitmq.Exchange{
+			{Name: "orders", Vhost: "/", Type: "direct"},
+			{Name: "orders.dlx", Vhost: "/", Type: "fanout"},
+		},
+		Queues: []rabbitmq.Queue{{Name: "orders", Vhost: "/", Arguments: map[string]any{"x-dead-letter-exchange": "orders.dlx"}}},
+	}
+	opts := cli.Options{FilterExchange: "orders"}
+
+	out := diagram.Generate(topo.Filter(opts), opts)
+	assert.Contains(t, out, "rectangle \"exchange: orders.dlx\\n(filtered out)\" as ex___orders_dlx #BBBBBB\n")
+	assert.NotContains(t, out, "not declared")
+}
+
+func TestGenerate_AlternateExchangeEdges(t *testing.T) {
+	topo := &rabbitmq.Topology{
+		Exchanges: []rabbitmq.Exchange{
+			{Name: "orders", Vhost: "/", Type: "direct", Arguments: map[string]any{"alternate-exchange": "unrouted"}},
+			{Name: "unrouted", Vhost: "/", Type: "fanout"},
+		},
+	}
+
+	out := diagram.Generate(topo, cli.Options{})
+	assert.Contains(t, out, "ex___orders -[#616161,dotted]-> ex___unrouted : \"alternate\"\n")
+	assert.Contains(t, out, "alternate exchange (AE)")
+	assert.NotContains(t, out, "dead-letter exchange (DLX)")
+}
+
+func TestGenerate_NoFailurePathsNoLegend(t *testing.T) {
+	assert.NotContains(t, diagram.Generate(sampleTopology(), cli.Options{}), "legend")
+}
@@ -1,4 +1,5 @@
-// Package diagram provides functions to generate PlantUML diagrams for a RabbitMQ topology.
+// Package diagram provides functions to render a RabbitMQ topology as PlantUML,
+// Graphviz DOT or Mermaid diagrams, or as machine-readable JSON/YAML exports.
 package diagram
 
 import (
@@ -6,73 +7,125 @@ import (
 	"hash/fnv"
 	"sort"
 	"strings"
+	"unicode"
 
 	"github.com/Patrick-Ivann/AIM-Q/internal/cli"
 	"github.com/Patrick-Ivann/AIM-Q/internal/rabbitmq"
+	"github.com/Patrick-Ivann/AIM-Q/internal/redact"
+	"github.com/Patrick-Ivann/AIM-Q/internal/routing"
+)
+
+// Labels of the edges that are not bindings.
+const (
+	deliversLabel  = "delivers"
+	alternateLabel = "alternate"
 )
 
 // Generate produces PlantUML source code visualizing the given RabbitMQ topology
 // based on CLI options (e.g., groupings, message stats, etc).
 func Generate(topology *rabbitmq.Topology, opts cli.Options) string {
+	return generate(topology, opts, nil)
+}
+
+// GenerateTrace is Generate highlighting the nodes and edges traversed by the
+// simulated message of result, and fading the rest.
+func GenerateTrace(topology *rabbitmq.Topology, opts cli.Options, result *routing.Result) string {
+	return generate(topology, opts, newTrace(result))
+}
+
+// generate produces the PlantUML source of Generate and GenerateTrace.
+func generate(topology *rabbitmq.Topology, opts cli.Options, tr *trace) string {
 	var sb strings.Builder
 
 	// Begin PlantUML document and set visual options
-	sb.WriteString(fmt.Sprintf("@startuml %s\n", opts.URI))
+	sb.WriteString("@startuml\n")
+	if t := title(opts); t != "" {
+		sb.WriteString(fmt.Sprintf("title %s\n", escapeLabel(t)))
+	}
+	if tr != nil {
+		sb.WriteString(fmt.Sprintf("caption %s\n", escapeLabel(tr.caption())))
+	}
 	sb.WriteString("skinparam shadowing false\n\n")
 
-	// Compute diagram groups (vhost or type)
-	groupKey := determineGroups(topology, opts)
-
 	// Track already-defined exchanges to avoid duplicate renderings.
 	definedExchanges := make(map[string]struct{})
 
-	for _, group := range groupKey {
-		writeDiagramGroup(&sb, topology, opts, group, definedExchanges)
+	for _, cluster := range topology.Clusters() {
+		writeDiagramCluster(&sb, topology.InCluster(cluster), opts, cluster, definedExchanges, tr)
 	}
+	// Tell objects hidden by the filters from missing ones in placeholders.
+	declared := declaredNodes(topology.Unfiltered())
+	writeFailurePaths(&sb, topology, definedExchanges, declared, tr)
+	writeLinks(&sb, topology, definedExchanges, declared, tr)
 
 	sb.WriteString("@enduml\n")
 	return sb.String()
 }
 
-// writeDiagramGroup emits one diagram group (package block) for PlantUML rendering.
+// writeDiagramCluster emits the groups of one cluster, wrapped in a node block
+// when the topology merges several clusters.
+func writeDiagramCluster(
+	sb *strings.Builder, topology *rabbitmq.Topology, opts cli.Options,
+	cluster string, definedExchanges map[string]struct{}, tr *trace,
+) {
+	if cluster != "" {
+		sb.WriteString(fmt.Sprintf("node \"%s\" as cluster_%s {\n", escapeLabel(cluster), clusterAlias(cluster)))
+	}
+
+	// Compute diagram groups (vhost or type)
+	for i, group := range determineGroups(topology, opts) {
+		header := fmt.Sprintf("package \"%s\" {\n", group)
+		if cluster != "" {
+			// Group names repeat across clusters: give each package a unique alias.
+			header = fmt.Sprintf("package \"%s\" as group_%s_%d {\n", group, clusterAlias(cluster), i)
+		}
+		sb.WriteString(header)
+		writeDiagramGroup(sb, topology, opts, group, definedExchanges, tr)
+		sb.WriteString("}\n")
+	}
+
+	if cluster != "" {
+		sb.WriteString("}\n")
+	}
+}
+
+// writeDiagramGroup emits the content of one diagram group (package block) for PlantUML rendering.
 // This includes exchanges, queues, bindings, and consumers.
 func writeDiagramGroup(
 	sb *strings.Builder, topology *rabbitmq.Topology, opts cli.Options,
-	group string, definedExchanges map[string]struct{},
+	group string, definedExchanges map[string]struct{}, tr *trace,
 ) {
-	sb.WriteString(fmt.Sprintf("package \"%s\" {\n", group))
-	writeExchanges(sb, topology.Exchanges, opts, group, definedExchanges)
-	writeQueues(sb, topology.Queues, opts, group)
-	writeBindings(sb, topology.Bindings, opts, group, definedExchanges)
-	writeConsumers(sb, topology.Consumers, opts, group)
-	sb.WriteString("}\n")
+	writeExchanges(sb, topology.Exchanges, opts, group, definedExchanges, tr)
+	writeQueues(sb, topology.Queues, opts, group, tr)
+	writeBindings(sb, topology.Bindings, opts, group, definedExchanges, tr)
+	writeConsumers(sb, topology.Consumers, opts, group, tr)
 }
 
 // writeExchanges emits rectangle definitions for exchanges belonging to the group.
 func writeExchanges(
 	sb *strings.Builder, exchanges []rabbitmq.Exchange, opts cli.Options,
-	group string, definedExchanges map[string]struct{},
+	group string, definedExchanges map[string]struct{}, tr *trace,
 ) {
 	for _, ex := range exchanges {
 		if !matchesGroup(opts, ex.Vhost, ex.Type, group) {
 			continue
 		}
-		exID := sanitize("ex_" + ex.Vhost + "_" + ex.Name)
+		exID := nodeID("ex", ex.Cluster, ex.Vhost, ex.Name)
 		definedExchanges[exID] = struct{}{}
-		label := fmt.Sprintf("%s exchange: %s\\n(type=%s)", icon(ex.Type), ex.Name, ex.Type)
-		sb.WriteString(fmt.Sprintf("rectangle \"%s\" as %s #%s\n", label, exID, color(ex.Type)))
+		label := fmt.Sprintf("%s exchange: %s\\n(type=%s)", rabbitmq.ExchangeIcon(ex.Type), ex.Name, ex.Type)
+		sb.WriteString(fmt.Sprintf("rectangle \"%s\" as %s%s\n", label, exID, tr.fill(exID, color(ex.Type))))
 	}
 }
 
 // writeQueues emits rectangle definitions for queues belonging to the group.
 func writeQueues(
-	sb *strings.Builder, queues []rabbitmq.Queue, opts cli.Options, group string,
+	sb *strings.Builder, queues []rabbitmq.Queue, opts cli.Options, group string, tr *trace,
 ) {
 	for _, q := range queues {
 		if !matchesGroup(opts, q.Vhost, "", group) {
 			continue
 		}
-		qID := sanitize("qu_" + q.Vhost + "_" + q.Name)
+		qID := nodeID("qu", q.Cluster, q.Vhost, q.Name)
 		label := fmt.Sprintf("📦 queue: %s", q.Name)
 
 		if opts.ShowMsgStats {
@@ -81,14 +134,14 @@ func writeQueues(
 				label += fmt.Sprintf("\\nmsgs: %v", msgs)
 			}
 		}
-		sb.WriteString(fmt.Sprintf("rectangle \"%s\" as %s #white\n", label, qID))
+		sb.WriteString(fmt.Sprintf("rectangle \"%s\" as %s%s\n", label, qID, tr.fill(qID, "white")))
 	}
 }
 
 // writeBindings emits PlantUML arrows for all queue & exchange linkages in this group.
 func writeBindings(
 	sb *strings.Builder, bindings []rabbitmq.Binding, opts cli.Options,
-	group string, definedExchanges map[string]struct{},
+	group string, definedExchanges map[string]struct{}, tr *trace,
 ) {
 	for _, b := range bindings {
 		if !matchesGroup(opts, b.Vhost, "", group) {
@@ -100,58 +153,54 @@ func writeBindings(
 		if source == "" {
 			source = "default"
 		}
-		src := sanitize("ex_" + b.Vhost + "_" + source)
+		src := nodeID("ex", b.Cluster, b.Vhost, source)
 		if _, exists := definedExchanges[src]; !exists {
 			definedExchanges[src] = struct{}{}
 			label := "➡️ exchange: default\\n(type=direct)"
-			sb.WriteString(fmt.Sprintf("rectangle \"%s\" as %s #%s\n", label, src, vhostColor(b.Vhost)))
+			sb.WriteString(fmt.Sprintf("rectangle \"%s\" as %s%s\n", label, src, tr.fill(src, strings.TrimPrefix(vhostColor(b.Vhost), "#"))))
 		}
 
 		// Connections: source → destination (queue or exchange)
 		var dst string
 		if b.DestType == "queue" {
-			dst = sanitize("qu_" + b.Vhost + "_" + b.Destination)
+			dst = nodeID("qu", b.Cluster, b.Vhost, b.Destination)
 		} else {
-			dst = sanitize("ex_" + b.Vhost + "_" + b.Destination)
+			dst = nodeID("ex", b.Cluster, b.Vhost, b.Destination)
 		}
 
 		label := ""
 		if b.RoutingKey != "" {
 			label = fmt.Sprintf(" : \"%s\"", escapeLabel(b.RoutingKey))
 		}
-		sb.WriteString(fmt.Sprintf("%s --> %s%s\n", src, dst, label))
+		arrow := tr.arrow(edgeKey(src, dst, b.RoutingKey), "", "")
+		sb.WriteString(fmt.Sprintf("%s %s %s%s\n", src, arrow, dst, label))
 	}
 }
 
 // writeConsumers emits PlantUML "actor" and delivery edges for consumer processes.
 func writeConsumers(
-	sb *strings.Builder, consumers []rabbitmq.Consumer, opts cli.Options, group string,
+	sb *strings.Builder, consumers []rabbitmq.Consumer, opts cli.Options, group string, tr *trace,
 ) {
 	for _, c := range consumers {
 		if !matchesGroup(opts, c.Vhost, "", group) {
 			continue
 		}
-		qID := sanitize("qu_" + c.Vhost + "_" + c.Queue)
-		conID := sanitize("cons_" + c.ConsumerTag)
-		sb.WriteString(fmt.Sprintf("actor \"consumer: %s\" as %s\n", c.ConsumerTag, conID))
-		sb.WriteString(fmt.Sprintf("%s --> %s : delivers\n", qID, conID))
+		qID := nodeID("qu", c.Cluster, c.Vhost, c.Queue)
+		conID := consumerID(c.Cluster, c.ConsumerTag)
+		tr.deliver(qID, conID)
+		arrow := tr.arrow(edgeKey(qID, conID, deliversLabel), "", "")
+		sb.WriteString(fmt.Sprintf("actor \"consumer: %s\" as %s%s\n", c.ConsumerTag, conID, tr.fill(conID, "")))
+		sb.WriteString(fmt.Sprintf("%s %s %s : %s\n", qID, arrow, conID, deliversLabel))
 	}
 }
 
-// icon returns an emoji prefix based on exchange type for visual clarity.
-func icon(t string) string {
-	switch t {
-	case "direct":
-		return "➡️"
-	case "fanout":
-		return "🔄"
-	case "topic":
-		return "🧩"
-	case "headers":
-		return "📋"
-	default:
-		return "❓"
+// title returns the diagram title: the explicit --title, or the broker URI
+// with its credentials redacted.
+func title(opts cli.Options) string {
+	if opts.Title != "" {
+		return opts.Title
 	}
+	return redact.URI(opts.URI)
 }
 
 // color maps exchange type to a PlantUML color string.
@@ -188,6 +237,36 @@ func sanitize(s string) string {
 	return replacer.Replace(s)
 }
 
+// nodeID returns the PlantUML and DOT alias of an exchange ("ex") or queue ("qu").
+//
+// The cluster is only part of the alias in merged multi-cluster topologies, so
+// the aliases of single-broker diagrams do not change.
+func nodeID(kind, cluster, vhost, name string) string {
+	if cluster != "" {
+		kind += "_" + clusterAlias(cluster)
+	}
+	return sanitize(kind + "_" + vhost + "_" + name)
+}
+
+// consumerID returns the PlantUML and DOT alias of a consumer.
+func consumerID(cluster, tag string) string {
+	if cluster != "" {
+		return sanitize("cons_" + clusterAlias(cluster) + "_" + tag)
+	}
+	return sanitize("cons_" + tag)
+}
+
+// clusterAlias returns the cluster name with every character other than an
+// ASCII letter or digit replaced by "_", such as the "@" of "rabbit@node1".
+func clusterAlias(cluster string) string {
+	return strings.Map(func(r rune) rune {
+		if r < 128 && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			return r
+		}
+		return '_'
+	}, cluster)
+}
+
 // escapeLabel safely escapes routing keys and other labels for PlantUML.
 func escapeLabel(s string) string {
 	s = strings.ReplaceAll(s, "\"", "\\\"")
@@ -0,0 +1,163 @@
+package diagram
+
+import (
+	"cmp"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/Patrick-Ivann/AIM-Q/internal/rabbitmq"
+	"github.com/Patrick-Ivann/AIM-Q/internal/redact"
+)
+
+// Edge colors of the links between brokers, distinct from bindings and deliveries.
+const (
+	shovelColor     = "EF6C00"
+	federationColor = "6A1B9A"
+)
+
+// endpoint is one end of a shovel or federation link: a queue or exchange of
+// the broker itself, or of the remote broker at URI. The empty exchange name
+// designates the default exchange.
+type endpoint struct {
+	URI   string
+	Vhost string
+	Kind  string // "ex" or "qu"
+	Name  string
+}
+
+// writeLinks emits the shovels and federation links of the topology as edges
+// between local queues or exchanges and cloud nodes standing for remote brokers.
+// In merged multi-cluster topologies, remote brokers of a merged cluster are
+// resolved to the queues and exchanges of that cluster instead.
+//
+// Local queues and exchanges missing from the diagram are drawn as placeholders,
+// as the targets of failure paths are.
+func writeLinks(
+	sb *strings.Builder, topology *rabbitmq.Topology, definedExchanges, declared map[string]struct{}, tr *trace,
+) {
+	nodes := linkNodes{
+		exchanges: definedExchanges,
+		queues:    make(map[string]struct{}, len(topology.Queues)),
+		remotes:   make(map[string]struct{}),
+		declared:  declared,
+		topology:  topology,
+	}
+	for _, q := range topology.Queues {
+		nodes.queues[nodeID("qu", q.Cluster, q.Vhost, q.Name)] = struct{}{}
+	}
+
+	for _, s := range topology.Shovels {
+		src := writeEndpoint(sb, shovelSource(s), s.Cluster, nodes, tr)
+		dst := writeEndpoint(sb, shovelDestination(s), s.Cluster, nodes, tr)
+		label := "shovel: " + s.Name
+		arrow := tr.arrow(edgeKey(src, dst, label), shovelColor, "bold")
+		sb.WriteString(fmt.Sprintf("%s %s %s : \"%s\"\n", src, arrow, dst, escapeLabel(label)))
+	}
+
+	for _, l := range topology.FederationLinks {
+		upstream := writeEndpoint(sb, federationUpstream(l), l.Cluster, nodes, tr)
+		local := writeEndpoint(sb, federationLocal(l), l.Cluster, nodes, tr)
+		label := "federation: " + l.Upstream
+		arrow := tr.arrow(edgeKey(upstream, local, label), federationColor, "bold")
+		sb.WriteString(fmt.Sprintf("%s %s %s : \"%s\"\n", upstream, arrow, local, escapeLabel(label)))
+	}
+}
+
+// linkNodes holds the aliases of the nodes the endpoints of links may refer to.
+type linkNodes struct {
+	exchanges map[string]struct{} // Exchanges of the diagram
+	queues    map[string]struct{} // Queues of the diagram
+	remotes   map[string]struct{} // Cloud nodes of remote endpoints
+	declared  map[string]struct{} // Exchanges and queues of the unfiltered topology
+	topology  *rabbitmq.Topology  // Topology of the diagram, mapping hosts to merged clusters
+}
+
+// writeEndpoint returns the alias of the endpoint, emitting a cloud node the
+// first time a remote endpoint is seen. Local endpoints are the exchange and
+// queue nodes of the cluster, or placeholders if the diagram lacks them; so are
+// the endpoints on a broker of a merged cluster, in that cluster.
+func writeEndpoint(sb *strings.Builder, e endpoint, cluster string, nodes linkNodes, tr *trace) string {
+	host, vhost := parseAMQPURI(e.URI, e.Vhost)
+	if merged, ok := nodes.topology.HostCluster((&url.URL{Host: host}).Hostname()); ok && host != "" {
+		cluster, host = merged, ""
+	}
+	switch {
+	case host == "" && e.Kind == "qu":
+		return ensureQueue(sb, cluster, vhost, e.Name, nodes.queues, nodes.declared, tr)
+	case host == "":
+		return ensureExchange(sb, cluster, vhost, e.Name, nodes.exchanges, nodes.declared, tr)
+	}
+
+	name := e.Name
+	if e.Kind == "ex" {
+		name = exchangeName(name)
+	}
+	id := "remote_" + clusterAlias(host+"_"+vhost+"_"+e.Kind+"_"+name)
+	if _, exists := nodes.remotes[id]; !exists {
+		nodes.remotes[id] = struct{}{}
+		label := fmt.Sprintf("%s\\n%s: %s", redact.URI(e.URI), kindLabel(e.Kind), name)
+		sb.WriteString(fmt.Sprintf("cloud \"%s\" as %s%s\n", escapeLabel(label), id, tr.fill(id, "")))
+	}
+	return id
+}
+
+// parseAMQPURI returns the host of an AMQP URI and the vhost it designates,
+// defaulting to vhost. URIs without host, such as "amqp://", designate the
+// broker itself and return an empty host.
+func parseAMQPURI(raw, vhost string) (string, string) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return raw, vhost
+	}
+	if path := strings.TrimPrefix(u.Path, "/"); path != "" {
+		vhost = path
+	}
+	return u.Host, vhost
+}
+
+// shovelSource returns the queue or exchange a shovel consumes from.
+func shovelSource(s rabbitmq.Shovel) endpoint {
+	if s.SrcQueue != "" {
+		return endpoint{URI: s.SrcURI, Vhost: s.Vhost, Kind: "qu", Name: s.SrcQueue}
+	}
+	return endpoint{URI: s.SrcURI, Vhost: s.Vhost, Kind: "ex", Name: s.SrcExchange}
+}
+
+// shovelDestination returns the queue or exchange a shovel publishes to.
+func shovelDestination(s rabbitmq.Shovel) endpoint {
+	if s.DestQueue != "" {
+		return endpoint{URI: s.DestURI, Vhost: s.Vhost, Kind: "qu", Name: s.DestQueue}
+	}
+	return endpoint{URI: s.DestURI, Vhost: s.Vhost, Kind: "ex", Name: s.DestExchange}
+}
+
+// federationUpstream returns the upstream exchange or queue of a federation
+// link, which has the name of the local one unless configured otherwise.
+func federationUpstream(l rabbitmq.FederationLink) endpoint {
+	if l.Type == "queue" {
+		return endpoint{URI: l.URI, Vhost: l.Vhost, Kind: "qu", Name: cmp.Or(l.UpstreamQueue, l.Queue)}
+	}
+	return endpoint{URI: l.URI, Vhost: l.Vhost, Kind: "ex", Name: cmp.Or(l.UpstreamExchange, l.Exchange)}
+}
+
+// federationLocal returns the federated exchange or queue of the broker itself.
+func federationLocal(l rabbitmq.FederationLink) endpoint {
+	if l.Type == "queue" {
+		return endpoint{Vhost: l.Vhost, Kind: "qu", Name: l.Queue}
+	}
+	return endpoint{Vhost: l.Vhost, Kind: "ex", Name: l.Exchange}
+}
+
+// exchangeName returns the node name of an exchange, "default" for the default exchange.
+func exchangeName(name string) string {
+	return cmp.Or(name, "default")
+}
+
+// kindLabel returns the label prefix of a node kind.
+func kindLabel(kind string) string {
+	if kind == "qu" {
+		return "queue"
+	}
+	return "exchange"
+}
@@ -0,0 +1,190 @@
+package diagram
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Patrick-Ivann/AIM-Q/internal/cli"
+	"github.com/Patrick-Ivann/AIM-Q/internal/rabbitmq"
+)
+
+// mermaidExchangeTypes lists the exchange types that get a dedicated Mermaid class.
+var mermaidExchangeTypes = []string{"direct", "fanout", "topic", "headers", "other"}
+
+// GenerateMermaid produces a Mermaid flowchart visualizing the given RabbitMQ topology,
+// suitable for embedding in Markdown documents rendered by GitHub.
+//
+// Groups (vhost or type) are rendered as subgraphs, nested in one subgraph per
+// RabbitMQ cluster for merged multi-cluster topologies, and exchanges are styled per type.
+func GenerateMermaid(topology *rabbitmq.Topology, opts cli.Options) string {
+	var sb strings.Builder
+
+	if t := title(opts); t != "" {
+		// The escapes of Go quoted strings are valid in YAML double-quoted scalars.
+		sb.WriteString(fmt.Sprintf("---\ntitle: %s\n---\n", strconv.Quote(t)))
+	}
+	sb.WriteString("flowchart LR\n")
+
+	definedExchanges := make(map[string]struct{})
+
+	for i, cluster := range topology.Clusters() {
+		writeMermaidCluster(&sb, topology.InCluster(cluster), opts, i, cluster, definedExchanges)
+	}
+
+	for _, typ := range mermaidExchangeTypes {
+		sb.WriteString(fmt.Sprintf("  classDef %s fill:#%s,color:#000\n", mermaidClass(typ), color(typ)))
+	}
+	return sb.String()
+}
+
+// writeMermaidCluster emits the group subgraphs of one RabbitMQ cluster, wrapped
+// in an outer subgraph when the topology merges several clusters.
+func writeMermaidCluster(
+	sb *strings.Builder, topology *rabbitmq.Topology, opts cli.Options,
+	index int, cluster string, definedExchanges map[string]struct{},
+) {
+	prefix := "group_"
+	if cluster != "" {
+		prefix = fmt.Sprintf("group_%d_", index)
+		sb.WriteString(fmt.Sprintf("  subgraph cluster_%d [%s]\n", index, mermaidLabel(cluster)))
+	}
+
+	for i, group := range determineGroups(topology, opts) {
+		sb.WriteString(fmt.Sprintf("  subgraph %s%d [%s]\n", prefix, i, mermaidLabel(group)))
+		writeMermaidExchanges(sb, topology.Exchanges, opts, group, definedExchanges)
+		writeMermaidQueues(sb, topology.Queues, opts, group)
+		writeMermaidBindings(sb, topology.Bindings, opts, group, definedExchanges)
+		writeMermaidConsumers(sb, topology.Consumers, opts, group)
+		sb.WriteString("  end\n")
+	}
+
+	if cluster != "" {
+		sb.WriteString("  end\n")
+	}
+}
+
+// writeMermaidExchanges emits exchange nodes for the group, styled by exchange type.
+func writeMermaidExchanges(
+	sb *strings.Builder, exchanges []rabbitmq.Exchange, opts cli.Options,
+	group string, definedExchanges map[string]struct{},
+) {
+	for _, ex := range exchanges {
+		if !matchesGroup(opts, ex.Vhost, ex.Type, group) {
+			continue
+		}
+		exID := mermaidID("ex", ex.Cluster, ex.Vhost, ex.Name)
+		definedExchanges[exID] = struct{}{}
+		label := fmt.Sprintf("%s exchange: %s\n(type=%s)", rabbitmq.ExchangeIcon(ex.Type), ex.Name, ex.Type)
+		sb.WriteString(fmt.Sprintf("    %s[%s]:::%s\n", exID, mermaidLabel(label), mermaidClass(ex.Type)))
+	}
+}
+
+// writeMermaidQueues emits rounded queue nodes for the group.
+func writeMermaidQueues(sb *strings.Builder, queues []rabbitmq.Queue, opts cli.Options, group string) {
+	for _, q := range queues {
+		if !matchesGroup(opts, q.Vhost, "", group) {
+			continue
+		}
+		label := "📦 queue: " + q.Name
+		if opts.ShowMsgStats {
+			label += strings.ReplaceAll(formatMsgStats(q), "\\n", "\n")
+		}
+		sb.WriteString(fmt.Sprintf("    %s(%s)\n", mermaidID("qu", q.Cluster, q.Vhost, q.Name), mermaidLabel(label)))
+	}
+}
+
+// writeMermaidBindings emits binding edges for the group, labelled with their routing key.
+func writeMermaidBindings(
+	sb *strings.Builder, bindings []rabbitmq.Binding, opts cli.Options,
+	group string, definedExchanges map[string]struct{},
+) {
+	for _, b := range bindings {
+		if !matchesGroup(opts, b.Vhost, "", group) {
+			continue
+		}
+
+		// Ensure exchange source is always rendered: special-case for default ("")
+		src := mermaidID("ex", b.Cluster, b.Vhost, b.Source)
+		if _, exists := definedExchanges[src]; !exists {
+			definedExchanges[src] = struct{}{}
+			label := mermaidLabel("➡️ exchange: default\n(type=direct)")
+			sb.WriteString(fmt.Sprintf("    %s[%s]:::%s\n", src, label, mermaidClass("direct")))
+		}
+
+		dst := mermaidID("ex", b.Cluster, b.Vhost, b.Destination)
+		if b.DestType == "queue" {
+			dst = mermaidID("qu", b.Cluster, b.Vhost, b.Destination)
+		}
+
+		arrow := "-->"
+		if b.RoutingKey != "" {
+			arrow = fmt.Sprintf("-->|%s|", mermaidLabel(b.RoutingKey))
+		}
+		sb.WriteString(fmt.Sprintf("    %s %s %s\n", src, arrow, dst))
+	}
+}
+
+// writeMermaidConsumers emits circle nodes for consumers and dotted delivery edges.
+func writeMermaidConsumers(sb *strings.Builder, consumers []rabbitmq.Consumer, opts cli.Options, group string) {
+	for _, c := range consumers {
+		if !matchesGroup(opts, c.Vhost, "", group) {
+			continue
+		}
+		conID := mermaidID("cons", c.Cluster, "", c.ConsumerTag)
+		sb.WriteString(fmt.Sprintf("    %s((%s))\n", conID, mermaidLabel("consumer: "+c.ConsumerTag)))
+		sb.WriteString(fmt.Sprintf("    %s -.->|delivers| %s\n", mermaidID("qu", c.Cluster, c.Vhost, c.Queue), conID))
+	}
+}
+
+// mermaidID builds a Mermaid node identifier from a kind prefix, cluster, vhost and name.
+//
+// The cluster is only encoded when set, so single-broker identifiers do not change.
+// Unlike sanitize, the encoding is injective: Mermaid only accepts [A-Za-z0-9_]
+// in identifiers, so every other byte, "_" included, is hex-escaped as "_xx" and
+// the parts are joined with "__", which an escape never produces.
+// This keeps "a.b" and "a-b" distinct and never yields reserved words such as "end".
+func mermaidID(kind, cluster, vhost, name string) string {
+	parts := []string{vhost, name}
+	if cluster != "" {
+		parts = append([]string{cluster}, parts...)
+	}
+
+	var sb strings.Builder
+	sb.WriteString(kind)
+	for _, part := range parts {
+		sb.WriteString("__")
+		for _, c := range []byte(part) {
+			switch {
+			case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+				sb.WriteByte(c)
+			default:
+				sb.WriteString(fmt.Sprintf("_%02x", c))
+			}
+		}
+	}
+	return sb.String()
+}
+
+// mermaidLabel returns s as a quoted Mermaid label, using entity codes for
+// characters that would otherwise terminate or alter the label.
+func mermaidLabel(s string) string {
+	replacer := strings.NewReplacer(
+		"\"", "#quot;",
+		"<", "#lt;",
+		">", "#gt;",
+		"|", "#124;",
+		"\n", "<br/>",
+	)
+	return "\"" + replacer.Replace(s) + "\""
+}
+
+// mermaidClass returns the class name used to style exchanges of the given type.
+func mermaidClass(t string) string {
+	switch t {
+	case "direct", "fanout", "topic", "headers":
+		return "exchange_" + t
+	default:
+		return "exchange_other"
+	}
+}
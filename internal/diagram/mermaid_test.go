@@ -0,0 +1,67 @@
+package diagram_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Patrick-Ivann/AIM-Q/internal/cli"
+	"github.com/Patrick-Ivann/AIM-Q/internal/diagram"
+	"github.com/Patrick-Ivann/AIM-Q/internal/rabbitmq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestGenerateMermaid(t *testing.T) {
+	out := diagram.GenerateMermaid(sampleTopology(), cli.Options{})
+
+	assert.True(t, strings.HasPrefix(out, "flowchart LR\n"))
+
+	// One subgraph per vhost, each closed with "end".
+	assert.Contains(t, out, "  subgraph group_0 [\"/\"]\n")
+	assert.Contains(t, out, "  subgraph group_1 [\"prod\"]\n")
+	assert.Equal(t, 2, strings.Count(out, "\n  end\n"))
+
+	// Identifiers only contain [A-Za-z0-9_] and labels escape quotes.
+	assert.Contains(t, out, `ex___2f__orders["🧩 exchange: orders<br/>(type=topic)"]:::exchange_topic`)
+	assert.Contains(t, out, `ex___2f__orders -->|"order.#quot;created#quot;"| qu___2f__order_2ecreated`)
+
+	// The default exchange is synthesized for bindings that reference it.
+	assert.Contains(t, out, `ex__prod__["➡️ exchange: default<br/>(type=direct)"]:::exchange_direct`)
+	assert.Contains(t, out, `qu__prod__invoices -.->|delivers| cons____ctag_2d1`)
+
+	assert.Contains(t, out, "classDef exchange_topic fill:#4CAF50,color:#000\n")
+}
+
+func TestGenerateMermaid_Title(t *testing.T) {
+	title := "Orders: \"prod\" #1 <eu>\nby 'ops'"
+	out := diagram.GenerateMermaid(sampleTopology(), cli.Options{Title: title})
+
+	frontmatter, _, ok := strings.Cut(strings.TrimPrefix(out, "---\n"), "---\n")
+	require.True(t, ok, "the title is given in a frontmatter")
+	var got struct{ Title string }
+	require.NoError(t, yaml.Unmarshal([]byte(frontmatter), &got))
+	assert.Equal(t, title, got.Title)
+}
+
+func TestGenerateMermaid_DistinctIDs(t *testing.T) {
+	topo := sampleTopology()
+	topo.Queues = append(topo.Queues, topo.Queues[0], topo.Queues[0])
+	topo.Queues[2].Name = "order-created"
+	topo.Queues[3].Name = "order_created"
+
+	out := diagram.GenerateMermaid(topo, cli.Options{})
+
+	assert.Contains(t, out, "qu___2f__order_2ecreated(")
+	assert.Contains(t, out, "qu___2f__order_2dcreated(")
+	assert.Contains(t, out, "qu___2f__order_5fcreated(")
+}
+
+func TestGenerateMermaid_DistinctIDsAcrossParts(t *testing.T) {
+	topo := &rabbitmq.Topology{Queues: []rabbitmq.Queue{{Vhost: "a_", Name: "x"}, {Vhost: "a", Name: "_x"}}}
+
+	out := diagram.GenerateMermaid(topo, cli.Options{})
+
+	assert.Contains(t, out, "qu__a_5f__x(")
+	assert.Contains(t, out, "qu__a___5fx(")
+}
@@ -0,0 +1,47 @@
+package diagram
+
+import (
+	"fmt"
+
+	"github.com/Patrick-Ivann/AIM-Q/internal/cli"
+	"github.com/Patrick-Ivann/AIM-Q/internal/rabbitmq"
+	"github.com/Patrick-Ivann/AIM-Q/pkg/render"
+)
+
+// init registers the built-in output formats.
+func init() {
+	render.Register("plantuml", render.NewRenderer("puml", renderPlantUML))
+	render.Register("dot", render.NewRenderer("dot", textRenderer(GenerateDOT)))
+	render.Register("mermaid", render.NewRenderer("mmd", textRenderer(GenerateMermaid)))
+	render.Register("json", render.NewRenderer("json", exportRenderer(GenerateJSON)))
+	render.Register("yaml", render.NewRenderer("yaml", exportRenderer(GenerateYAML)))
+}
+
+// textRenderer adapts a diagram generator returning source text to a RenderFunc.
+func textRenderer(generate func(*rabbitmq.Topology, cli.Options) string) render.RenderFunc {
+	return func(topology *rabbitmq.Topology, opts cli.Options) ([]byte, error) {
+		if err := rejectTraceRoute(opts); err != nil {
+			return nil, err
+		}
+		return []byte(generate(topology, opts)), nil
+	}
+}
+
+// exportRenderer adapts an export generator, which ignores display options, to a RenderFunc.
+func exportRenderer(generate func(*rabbitmq.Topology) (string, error)) render.RenderFunc {
+	return func(topology *rabbitmq.Topology, opts cli.Options) ([]byte, error) {
+		if err := rejectTraceRoute(opts); err != nil {
+			return nil, err
+		}
+		out, err := generate(topology)
+		return []byte(out), err
+	}
+}
+
+// rejectTraceRoute fails for formats that cannot highlight a traced route.
+func rejectTraceRoute(opts cli.Options) error {
+	if opts.TraceRoute != "" {
+		return fmt.Errorf("tracing a route is only supported by the plantuml format")
+	}
+	return nil
+}
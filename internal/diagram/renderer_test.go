@@ -0,0 +1,31 @@
+package diagram_test
+
+import (
+	"testing"
+
+	"github.com/Patrick-Ivann/AIM-Q/internal/cli"
+	"github.com/Patrick-Ivann/AIM-Q/pkg/render"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuiltinRenderers(t *testing.T) {
+	extensions := map[string]string{
+		"plantuml": "puml",
+		"dot":      "dot",
+		"mermaid":  "mmd",
+		"json":     "json",
+		"yaml":     "yaml",
+	}
+	for format, ext := range extensions {
+		t.Run(format, func(t *testing.T) {
+			r, err := render.Lookup(format)
+			require.NoError(t, err)
+			assert.Equal(t, ext, r.Extension())
+
+			out, err := r.Render(sampleTopology(), cli.Options{})
+			require.NoError(t, err)
+			assert.NotEmpty(t, out)
+		})
+	}
+}
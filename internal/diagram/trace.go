@@ -0,0 +1,169 @@
+package diagram
+
+import (
+	"cmp"
+	"fmt"
+
+	"github.com/Patrick-Ivann/AIM-Q/internal/cli"
+	"github.com/Patrick-Ivann/AIM-Q/internal/rabbitmq"
+	"github.com/Patrick-Ivann/AIM-Q/internal/routing"
+)
+
+// Colors of traced and faded elements.
+const (
+	traceColor     = "E65100"
+	fadedColor     = "BDBDBD"
+	fadedFill      = "EEEEEE"
+	fadedTextColor = "9E9E9E"
+)
+
+// trace holds the nodes and edges traversed by a simulated message, which
+// PlantUML diagrams highlight while fading the rest.
+//
+// A nil trace highlights and fades nothing, so writers can use it unconditionally.
+type trace struct {
+	result *routing.Result
+	nodes  map[string]struct{}
+	edges  map[string]struct{}
+}
+
+// renderPlantUML renders a PlantUML diagram, highlighting the route of the
+// message described by opts.TraceRoute, if set.
+//
+// The route is simulated on the unfiltered topology, so that it is followed
+// through the exchanges the filters hide, and highlighted on the filtered one.
+func renderPlantUML(topology *rabbitmq.Topology, opts cli.Options) ([]byte, error) {
+	if opts.TraceRoute == "" {
+		return []byte(Generate(topology, opts)), nil
+	}
+
+	msg, err := routing.ParseMessage(opts.TraceRoute)
+	if err != nil {
+		return nil, fmt.Errorf("invalid trace route: %w", err)
+	}
+	result, err := routing.Simulate(topology.Unfiltered(), msg)
+	if err != nil {
+		return nil, fmt.Errorf("tracing route: %w", err)
+	}
+	return []byte(GenerateTrace(topology, opts, result)), nil
+}
+
+// newTrace indexes the nodes and edges of the routes of result.
+func newTrace(result *routing.Result) *trace {
+	t := &trace{result: result, nodes: make(map[string]struct{}), edges: make(map[string]struct{})}
+	vhost := result.Message.Vhost
+
+	for _, route := range result.Routes {
+		for _, hop := range route.Path {
+			src := nodeID("ex", "", vhost, exchangeName(hop.Exchange))
+			kind := "qu"
+			if hop.DestType == "exchange" {
+				kind = "ex"
+			}
+			dst := nodeID(kind, "", vhost, hop.Destination)
+			t.add(src, dst, edgeKey(src, dst, hopLabel(hop, result.Message)))
+		}
+	}
+	return t
+}
+
+// hopLabel returns the label of the diagram edge a hop follows.
+func hopLabel(hop routing.Hop, msg routing.Message) string {
+	switch hop.Via {
+	case routing.ViaAlternate:
+		return alternateLabel
+	case routing.ViaDefault:
+		// Bindings of the default exchange use the queue name as routing key.
+		return msg.RoutingKey
+	default:
+		return hop.Binding.RoutingKey
+	}
+}
+
+// edgeKey identifies a diagram edge by its ends and label.
+func edgeKey(from, to, label string) string {
+	return from + " " + to + " " + label
+}
+
+// add marks the nodes and the edge between them as traversed.
+func (t *trace) add(from, to, edge string) {
+	t.nodes[from] = struct{}{}
+	t.nodes[to] = struct{}{}
+	t.edges[edge] = struct{}{}
+}
+
+// reached tells if the message reaches the node.
+func (t *trace) reached(id string) bool {
+	if t == nil {
+		return false
+	}
+	_, ok := t.nodes[id]
+	return ok
+}
+
+// deliver marks the delivery of the message from a reached queue to a consumer.
+func (t *trace) deliver(queueID, consumerID string) {
+	if t.reached(queueID) {
+		t.add(queueID, consumerID, edgeKey(queueID, consumerID, deliversLabel))
+	}
+}
+
+// fill returns the color specification of a node declaration, starting with a
+// space, from its fill color (without "#", "" for none).
+func (t *trace) fill(id, color string) string {
+	switch {
+	case t.reached(id):
+		return fmt.Sprintf(" #%s;line:%s;line.bold", cmp.Or(color, "white"), traceColor)
+	case t != nil:
+		return fmt.Sprintf(" #%s;line:%s;text:%s", fadedFill, fadedColor, fadedTextColor)
+	case color != "":
+		return " #" + color
+	default:
+		return ""
+	}
+}
+
+// arrow returns the PlantUML arrow of an edge with the given color (without
+// "#", "" for the default) and style ("dashed", "dotted", "bold" or "").
+func (t *trace) arrow(key, color, style string) string {
+	switch {
+	case t == nil:
+	case t.traversed(key):
+		color, style = traceColor, "bold"
+	default:
+		color = fadedColor
+	}
+	return styledArrow(color, style)
+}
+
+// traversed tells if the message follows the edge.
+func (t *trace) traversed(key string) bool {
+	_, ok := t.edges[key]
+	return ok
+}
+
+// caption returns the caption of a traced diagram describing the message and
+// how many queues receive it, followed by the warnings of the simulation, one per line.
+func (t *trace) caption() string {
+	msg := t.result.Message
+	caption := fmt.Sprintf("Route of a message published to exchange %s with routing key %q: %d queue(s)",
+		exchangeName(msg.Exchange), msg.RoutingKey, len(t.result.Routes))
+	for _, warning := range t.result.Warnings {
+		caption += "\nwarning: " + warning
+	}
+	return caption
+}
+
+// styledArrow returns a PlantUML arrow with the given color and style.
+func styledArrow(color, style string) string {
+	switch {
+	case color == "" && style == "":
+		return "-->"
+	case color == "":
+		return "-[" + style + "]->"
+	case style == "":
+		return "-[#" + color + "]->"
+	default:
+		return "-[#" + color + "," + style + "]->"
+	}
+}
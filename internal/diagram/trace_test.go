@@ -0,0 +1,99 @@
+package diagram_test
+
+import (
+	"testing"
+
+	"github.com/Patrick-Ivann/AIM-Q/internal/cli"
+	"github.com/Patrick-Ivann/AIM-Q/internal/rabbitmq"
+	"github.com/Patrick-Ivann/AIM-Q/pkg/render"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func traceTopology() *rabbitmq.Topology {
+	return &rabbitmq.Topology{
+		Exchanges: []rabbitmq.Exchange{
+			{Name: "orders", Vhost: "/", Type: "topic", Arguments: map[string]any{"alternate-exchange": "unrouted"}},
+			{Name: "unrouted", Vhost: "/", Type: "fanout"},
+		},
+		Queues: []rabbitmq.Queue{{Name: "eu", Vhost: "/"}, {Name: "us", Vhost: "/"}, {Name: "lost", Vhost: "/"}},
+		Bindings: []rabbitmq.Binding{
+			{Source: "orders", Vhost: "/", DestType: "queue", Destination: "eu", RoutingKey: "order.*.eu"},
+			{Source: "orders", Vhost: "/", DestType: "queue", Destination: "us", RoutingKey: "order.*.us"},
+			{Source: "unrouted", Vhost: "/", DestType: "queue", Destination: "lost"},
+		},
+		Consumers: []rabbitmq.Consumer{{Queue: "eu", ConsumerTag: "billing", Vhost: "/"}},
+	}
+}
+
+func TestRender_TraceRoute(t *testing.T) {
+	r, err := render.Lookup("plantuml")
+	require.NoError(t, err)
+
+	out, err := r.Render(traceTopology(), cli.Options{TraceRoute: "exchange=orders,key=order.created.eu"})
+	require.NoError(t, err)
+	s := string(out)
+
+	assert.Contains(t, s, "caption Route of a message published to exchange orders with routing key \\\"order.created.eu\\\": 1 queue(s)\n")
+	assert.Contains(t, s, "as ex___orders #4CAF50;line:E65100;line.bold\n")
+	assert.Contains(t, s, "as qu___eu #white;line:E65100;line.bold\n")
+	assert.Contains(t, s, "as qu___us #EEEEEE;line:BDBDBD;text:9E9E9E\n")
+	assert.Contains(t, s, "ex___orders -[#E65100,bold]-> qu___eu : \"order.*.eu\"\n")
+	assert.Contains(t, s, "ex___orders -[#BDBDBD]-> qu___us : \"order.*.us\"\n")
+	assert.Contains(t, s, "qu___eu -[#E65100,bold]-> cons_billing : delivers\n", "consumers of reached queues are highlighted")
+	assert.Contains(t, s, "ex___orders -[#BDBDBD,dotted]-> ex___unrouted : \"alternate\"\n")
+}
+
+func TestRender_TraceRouteThroughAlternateExchange(t *testing.T) {
+	r, err := render.Lookup("plantuml")
+	require.NoError(t, err)
+
+	out, err := r.Render(traceTopology(), cli.Options{TraceRoute: "exchange=orders,key=invoice.paid"})
+	require.NoError(t, err)
+	assert.Contains(t, string(out), "ex___orders -[#E65100,bold]-> ex___unrouted : \"alternate\"\n")
+	assert.Contains(t, string(out), "ex___unrouted -[#E65100,bold]-> qu___lost\n")
+}
+
+func TestRender_TraceRouteThroughFilteredExchange(t *testing.T) {
+	topology := traceTopology()
+	topology.Exchanges = append(topology.Exchanges, rabbitmq.Exchange{Name: "entry", Vhost: "/", Type: "fanout"})
+	topology.Bindings = append(topology.Bindings,
+		rabbitmq.Binding{Source: "entry", Vhost: "/", DestType: "exchange", Destination: "orders"})
+	opts := cli.Options{FilterExchange: "orders", TraceRoute: "exchange=entry,key=order.created.eu"}
+
+	r, err := render.Lookup("plantuml")
+	require.NoError(t, err)
+	out, err := r.Render(topology.Filter(opts), opts)
+	require.NoError(t, err, "the filtered out start exchange is found")
+	assert.Contains(t, string(out), ": 1 queue(s)\n")
+	assert.Contains(t, string(out), "ex___orders -[#E65100,bold]-> qu___eu : \"order.*.eu\"\n")
+}
+
+func TestRender_TraceRouteWarnings(t *testing.T) {
+	topology := traceTopology()
+	topology.Bindings = append(topology.Bindings,
+		rabbitmq.Binding{Source: "orders", Vhost: "/", DestType: "exchange", Destination: "missing", RoutingKey: "#"})
+
+	r, err := render.Lookup("plantuml")
+	require.NoError(t, err)
+	out, err := r.Render(topology, cli.Options{TraceRoute: "exchange=orders,key=order.created.eu"})
+	require.NoError(t, err)
+	assert.Contains(t, string(out), ": 1 queue(s)\\nwarning: exchange \\\"missing\\\" does not exist\n")
+}
+
+func TestRender_TraceRouteErrors(t *testing.T) {
+	plantuml, err := render.Lookup("plantuml")
+	require.NoError(t, err)
+
+	_, err = plantuml.Render(traceTopology(), cli.Options{TraceRoute: "key=order"})
+	assert.ErrorContains(t, err, "missing exchange field")
+	_, err = plantuml.Render(traceTopology(), cli.Options{TraceRoute: "exchange=missing"})
+	assert.ErrorContains(t, err, `exchange "missing" not found`)
+
+	for _, format := range []string{"dot", "mermaid", "json", "yaml"} {
+		r, err := render.Lookup(format)
+		require.NoError(t, err)
+		_, err = r.Render(traceTopology(), cli.Options{TraceRoute: "exchange=orders"})
+		assert.ErrorContains(t, err, "only supported by the plantuml format", format)
+	}
+}
@@ -0,0 +1,218 @@
+// Package diff compares two RabbitMQ topologies and reports the exchanges,
+// queues and bindings that were added, removed or changed between them.
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/Patrick-Ivann/AIM-Q/internal/rabbitmq"
+)
+
+// Status tells how an object differs between the old and the new topology.
+type Status string
+
+// Possible statuses of a Change.
+const (
+	Added     Status = "added"
+	Removed   Status = "removed"
+	Changed   Status = "changed"
+	Unchanged Status = "unchanged"
+)
+
+// FieldChange describes a field whose value differs between the two topologies.
+//
+// Argument changes are reported per key, as "arguments.<key>"; a missing
+// argument is reported as a nil value.
+type FieldChange struct {
+	Field string
+	Old   any
+	New   any
+}
+
+// Change describes one object of the union of both topologies.
+type Change[T any] struct {
+	Key    string        // Identity of the object, e.g. "vhost/name"
+	Status Status        // How the object differs
+	Old    *T            // Object in the old topology, nil when added
+	New    *T            // Object in the new topology, nil when removed
+	Fields []FieldChange // Differing fields, only set when changed
+}
+
+// Result holds the comparison of every exchange, queue and binding, sorted by key.
+//
+// Unchanged objects are included so renderers can draw the full topology.
+type Result struct {
+	Exchanges []Change[rabbitmq.Exchange]
+	Queues    []Change[rabbitmq.Queue]
+	Bindings  []Change[rabbitmq.Binding]
+}
+
+// Compare returns the differences between the before and after topologies.
+//
+// Exchanges and queues are identified by vhost and name; bindings by vhost,
+// source, destination type, destination, routing key and arguments, so a binding
+// whose routing key or arguments changed is reported as removed and added.
+func Compare(before, after *rabbitmq.Topology) *Result {
+	return &Result{
+		Exchanges: compare(before.Exchanges, after.Exchanges, ExchangeKey, exchangeFields),
+		Queues:    compare(before.Queues, after.Queues, QueueKey, queueFields),
+		Bindings:  compare(before.Bindings, after.Bindings, BindingKey, func(_, _ rabbitmq.Binding) []FieldChange { return nil }),
+	}
+}
+
+// HasChanges reports whether any object was added, removed or changed.
+func (r *Result) HasChanges() bool {
+	added, removed, changed := r.Counts()
+	return added+removed+changed > 0
+}
+
+// Counts returns the number of added, removed and changed objects of all kinds.
+func (r *Result) Counts() (added, removed, changed int) {
+	count := func(s Status) {
+		switch s {
+		case Added:
+			added++
+		case Removed:
+			removed++
+		case Changed:
+			changed++
+		}
+	}
+	for _, c := range r.Exchanges {
+		count(c.Status)
+	}
+	for _, c := range r.Queues {
+		count(c.Status)
+	}
+	for _, c := range r.Bindings {
+		count(c.Status)
+	}
+	return added, removed, changed
+}
+
+// ExchangeKey identifies an exchange across topologies.
+func ExchangeKey(ex rabbitmq.Exchange) string {
+	return ex.Vhost + "/" + ex.Name
+}
+
+// QueueKey identifies a queue across topologies.
+func QueueKey(q rabbitmq.Queue) string {
+	return q.Vhost + "/" + q.Name
+}
+
+// BindingKey identifies a binding across topologies.
+//
+// Bindings with arguments, such as those of headers exchanges, are also
+// identified by their arguments, which are appended in canonical form.
+func BindingKey(b rabbitmq.Binding) string {
+	key := fmt.Sprintf("%s/%s -> %s %s [%s]", b.Vhost, b.Source, b.DestType, b.Destination, b.RoutingKey)
+	if len(b.Arguments) > 0 {
+		key += " " + canonicalArguments(b.Arguments)
+	}
+	return key
+}
+
+// canonicalArguments encodes arguments as JSON, which sorts the keys of maps,
+// so that equal arguments always have the same encoding.
+func canonicalArguments(args map[string]any) string {
+	data, err := json.Marshal(args)
+	if err != nil {
+		// Arguments are decoded from JSON and always encode; fmt sorts map keys too.
+		return fmt.Sprint(args)
+	}
+	return string(data)
+}
+
+// compare matches objects of both lists by key and classifies each one.
+func compare[T any](before, after []T, key func(T) string, fields func(a, b T) []FieldChange) []Change[T] {
+	oldByKey := make(map[string]*T, len(before))
+	for i := range before {
+		oldByKey[key(before[i])] = &before[i]
+	}
+	newByKey := make(map[string]*T, len(after))
+	for i := range after {
+		newByKey[key(after[i])] = &after[i]
+	}
+
+	keys := make([]string, 0, len(oldByKey)+len(newByKey))
+	for k := range oldByKey {
+		keys = append(keys, k)
+	}
+	for k := range newByKey {
+		if _, ok := oldByKey[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	changes := make([]Change[T], 0, len(keys))
+	for _, k := range keys {
+		c := Change[T]{Key: k, Old: oldByKey[k], New: newByKey[k]}
+		switch {
+		case c.Old == nil:
+			c.Status = Added
+		case c.New == nil:
+			c.Status = Removed
+		default:
+			c.Fields = fields(*c.Old, *c.New)
+			c.Status = Unchanged
+			if len(c.Fields) > 0 {
+				c.Status = Changed
+			}
+		}
+		changes = append(changes, c)
+	}
+	return changes
+}
+
+// exchangeFields lists the differing fields of two versions of an exchange.
+func exchangeFields(a, b rabbitmq.Exchange) []FieldChange {
+	var changes []FieldChange
+	changes = appendIfDifferent(changes, "type", a.Type, b.Type)
+	changes = appendIfDifferent(changes, "durable", a.Durable, b.Durable)
+	changes = appendIfDifferent(changes, "auto_delete", a.AutoDelete, b.AutoDelete)
+	return append(changes, argumentChanges(a.Arguments, b.Arguments)...)
+}
+
+// queueFields lists the differing fields of two versions of a queue.
+//
+// Message statistics are runtime state and are not compared.
+func queueFields(a, b rabbitmq.Queue) []FieldChange {
+	var changes []FieldChange
+	changes = appendIfDifferent(changes, "durable", a.Durable, b.Durable)
+	changes = appendIfDifferent(changes, "auto_delete", a.AutoDelete, b.AutoDelete)
+	return append(changes, argumentChanges(a.Arguments, b.Arguments)...)
+}
+
+// appendIfDifferent appends a FieldChange when before and after differ.
+func appendIfDifferent(changes []FieldChange, field string, before, after any) []FieldChange {
+	if reflect.DeepEqual(before, after) {
+		return changes
+	}
+	return append(changes, FieldChange{Field: field, Old: before, New: after})
+}
+
+// argumentChanges compares two argument maps key by key, in key order.
+func argumentChanges(before, after map[string]any) []FieldChange {
+	keys := make(map[string]struct{}, len(before)+len(after))
+	for k := range before {
+		keys[k] = struct{}{}
+	}
+	for k := range after {
+		keys[k] = struct{}{}
+	}
+	sorted := make([]string, 0, len(keys))
+	for k := range keys {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+
+	var changes []FieldChange
+	for _, k := range sorted {
+		changes = appendIfDifferent(changes, "arguments."+k, before[k], after[k])
+	}
+	return changes
+}
@@ -0,0 +1,127 @@
+package diff_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/Patrick-Ivann/AIM-Q/internal/diff"
+	"github.com/Patrick-Ivann/AIM-Q/internal/rabbitmq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func beforeTopology() *rabbitmq.Topology {
+	return &rabbitmq.Topology{
+		Exchanges: []rabbitmq.Exchange{
+			{Name: "orders", Vhost: "/", Type: "topic", Durable: true},
+			{Name: "legacy", Vhost: "/", Type: "fanout"},
+		},
+		Queues: []rabbitmq.Queue{
+			{Name: "q1", Vhost: "/", Durable: true, Arguments: map[string]any{"x-message-ttl": 1000.0}},
+		},
+		Bindings: []rabbitmq.Binding{
+			{Source: "orders", Destination: "q1", DestType: "queue", Vhost: "/", RoutingKey: "order.*"},
+		},
+	}
+}
+
+func afterTopology() *rabbitmq.Topology {
+	return &rabbitmq.Topology{
+		Exchanges: []rabbitmq.Exchange{
+			{Name: "orders", Vhost: "/", Type: "topic", Durable: false},
+			{Name: "orders.v2", Vhost: "/", Type: "topic"},
+		},
+		Queues: []rabbitmq.Queue{
+			{Name: "q1", Vhost: "/", Durable: true, Arguments: map[string]any{"x-message-ttl": 2000.0, "x-queue-type": "quorum"}},
+		},
+		Bindings: []rabbitmq.Binding{
+			{Source: "orders", Destination: "q1", DestType: "queue", Vhost: "/", RoutingKey: "order.#"},
+		},
+	}
+}
+
+func TestCompare(t *testing.T) {
+	result := diff.Compare(beforeTopology(), afterTopology())
+
+	require.Len(t, result.Exchanges, 3)
+	assert.Equal(t, "//legacy", result.Exchanges[0].Key)
+	assert.Equal(t, diff.Removed, result.Exchanges[0].Status)
+	assert.Nil(t, result.Exchanges[0].New)
+
+	assert.Equal(t, diff.Changed, result.Exchanges[1].Status)
+	assert.Equal(t, []diff.FieldChange{{Field: "durable", Old: true, New: false}}, result.Exchanges[1].Fields)
+
+	assert.Equal(t, diff.Added, result.Exchanges[2].Status)
+	assert.Nil(t, result.Exchanges[2].Old)
+
+	require.Len(t, result.Queues, 1)
+	assert.Equal(t, []diff.FieldChange{
+		{Field: "arguments.x-message-ttl", Old: 1000.0, New: 2000.0},
+		{Field: "arguments.x-queue-type", Old: nil, New: "quorum"},
+	}, result.Queues[0].Fields)
+
+	// A binding whose routing key changed is a different binding.
+	require.Len(t, result.Bindings, 2)
+	assert.Equal(t, diff.Added, result.Bindings[0].Status)
+	assert.Equal(t, diff.Removed, result.Bindings[1].Status)
+
+	added, removed, changed := result.Counts()
+	assert.Equal(t, []int{2, 2, 2}, []int{added, removed, changed})
+}
+
+func TestCompare_HeadersBindings(t *testing.T) {
+	binding := func(args map[string]any) rabbitmq.Binding {
+		return rabbitmq.Binding{Source: "events", Destination: "q1", DestType: "queue", Vhost: "/", Arguments: args}
+	}
+	before := &rabbitmq.Topology{Bindings: []rabbitmq.Binding{
+		binding(map[string]any{"x-match": "all", "region": "eu", "type": "order"}),
+		binding(map[string]any{"x-match": "any", "region": "us"}),
+	}}
+	after := &rabbitmq.Topology{Bindings: []rabbitmq.Binding{
+		binding(map[string]any{"type": "order", "region": "eu", "x-match": "all"}),
+		binding(map[string]any{"x-match": "any", "region": "asia"}),
+	}}
+
+	result := diff.Compare(before, after)
+
+	require.Len(t, result.Bindings, 3)
+	assert.Equal(t, `//events -> queue q1 [] {"region":"asia","x-match":"any"}`, result.Bindings[0].Key)
+	assert.Equal(t, diff.Added, result.Bindings[0].Status)
+	assert.Equal(t, `//events -> queue q1 [] {"region":"eu","type":"order","x-match":"all"}`, result.Bindings[1].Key)
+	assert.Equal(t, diff.Unchanged, result.Bindings[1].Status, "argument order does not matter")
+	assert.Equal(t, diff.Removed, result.Bindings[2].Status)
+
+	var buf bytes.Buffer
+	require.NoError(t, result.WriteText(&buf))
+	assert.Contains(t, buf.String(), `  - events → queue q1 [] (vhost=/) {"region":"us","x-match":"any"}`+"\n")
+}
+
+func TestCompare_Identical(t *testing.T) {
+	result := diff.Compare(beforeTopology(), beforeTopology())
+
+	assert.False(t, result.HasChanges())
+	for _, c := range result.Exchanges {
+		assert.Equal(t, diff.Unchanged, c.Status)
+	}
+}
+
+func TestResult_WriteText(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, diff.Compare(beforeTopology(), afterTopology()).WriteText(&buf))
+
+	out := buf.String()
+	assert.Contains(t, out, "Exchanges:\n"+
+		"  - legacy (vhost=/, type=fanout)\n"+
+		"  ~ orders (vhost=/, type=topic)\n"+
+		"      durable: true → false\n"+
+		"  + orders.v2 (vhost=/, type=topic)\n")
+	assert.Contains(t, out, "      arguments.x-queue-type: (none) → quorum\n")
+	assert.Contains(t, out, "  + orders → queue q1 [order.#] (vhost=/)\n")
+	assert.Contains(t, out, "Summary: 2 added, 2 removed, 2 changed\n")
+}
+
+func TestResult_WriteText_NoChanges(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, diff.Compare(beforeTopology(), beforeTopology()).WriteText(&buf))
+	assert.Equal(t, "No changes.\n", buf.String())
+}
@@ -0,0 +1,85 @@
+package diff
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Patrick-Ivann/AIM-Q/internal/rabbitmq"
+)
+
+// statusSymbols prefixes each reported object in the text output.
+var statusSymbols = map[Status]string{
+	Added:   "+",
+	Removed: "-",
+	Changed: "~",
+}
+
+// WriteText writes a human-readable report of the added, removed and changed objects.
+//
+// Unchanged objects are omitted. Changed objects list each differing field as "old → new".
+func (r *Result) WriteText(w io.Writer) error {
+	var sb strings.Builder
+
+	if !r.HasChanges() {
+		sb.WriteString("No changes.\n")
+		_, err := io.WriteString(w, sb.String())
+		return err
+	}
+
+	writeSection(&sb, "Exchanges", r.Exchanges, func(ex rabbitmq.Exchange) string {
+		return fmt.Sprintf("%s (vhost=%s, type=%s)", ex.Name, ex.Vhost, ex.Type)
+	})
+	writeSection(&sb, "Queues", r.Queues, func(q rabbitmq.Queue) string {
+		return fmt.Sprintf("%s (vhost=%s)", q.Name, q.Vhost)
+	})
+	writeSection(&sb, "Bindings", r.Bindings, func(b rabbitmq.Binding) string {
+		line := fmt.Sprintf("%s → %s %s [%s] (vhost=%s)", b.Source, b.DestType, b.Destination, b.RoutingKey, b.Vhost)
+		if len(b.Arguments) > 0 {
+			line += " " + canonicalArguments(b.Arguments)
+		}
+		return line
+	})
+
+	added, removed, changed := r.Counts()
+	sb.WriteString(fmt.Sprintf("Summary: %d added, %d removed, %d changed\n", added, removed, changed))
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// writeSection writes the non-unchanged entries of one kind of object under a title.
+func writeSection[T any](sb *strings.Builder, title string, changes []Change[T], describe func(T) string) {
+	var lines []string
+	for _, c := range changes {
+		if c.Status == Unchanged {
+			continue
+		}
+		obj := c.New
+		if obj == nil {
+			obj = c.Old
+		}
+		lines = append(lines, fmt.Sprintf("  %s %s\n", statusSymbols[c.Status], describe(*obj)))
+		for _, f := range c.Fields {
+			lines = append(lines, fmt.Sprintf("      %s: %s → %s\n", f.Field, FormatValue(f.Old), FormatValue(f.New)))
+		}
+	}
+	if len(lines) == 0 {
+		return
+	}
+
+	sb.WriteString(title + ":\n")
+	for _, line := range lines {
+		sb.WriteString(line)
+	}
+	sb.WriteString("\n")
+}
+
+// FormatValue renders the old or new value of a FieldChange, showing missing
+// arguments as (none). Text reports and diff diagrams share it so they agree.
+func FormatValue(v any) string {
+	if v == nil {
+		return "(none)"
+	}
+	return fmt.Sprint(v)
+}
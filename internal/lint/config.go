@@ -0,0 +1,151 @@
+package lint
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// dateLayout is the layout of suppression expiry dates.
+const dateLayout = time.DateOnly
+
+// Config customizes the built-in rules. It is read from a YAML file:
+//
+//	production-vhosts: ^prod-
+//	ignore:                      # objects no rule reports, by name regex
+//	  - ^amq\.gen-
+//	rules:
+//	  queue-without-consumers:
+//	    enabled: false
+//	  non-durable-production-queue:
+//	    severity: error
+//	    vhosts:                  # severity overrides per vhost
+//	      prod-sandbox: info
+//	    ignore:
+//	      - ^cache\.
+//	suppressions:
+//	  - rule: dlx-missing-exchange
+//	    vhost: prod-eu
+//	    name: legacy.orders
+//	    expires: 2026-12-31
+//	    reason: DLX created by the next release
+//	suppressions-file: suppressions.yaml
+//
+// Names are matched against the Name of findings, so bindings are matched as
+// "source -> destination".
+type Config struct {
+	ProductionVhosts string                `yaml:"production-vhosts"` // Regular expression, DefaultProductionVhosts if empty
+	Ignore           []string              `yaml:"ignore"`            // Name regular expressions ignored by every rule
+	Rules            map[string]RuleConfig `yaml:"rules"`             // Settings per rule name
+	Suppressions     []Suppression         `yaml:"suppressions"`      // Accepted findings
+	// SuppressionsFile holds more suppressions, under a top-level
+	// "suppressions" key; a relative path is relative to the configuration file.
+	SuppressionsFile string `yaml:"suppressions-file"`
+}
+
+// RuleConfig customizes one rule.
+type RuleConfig struct {
+	Enabled  *bool               `yaml:"enabled"`  // Whether the rule runs, true if unset
+	Severity *Severity           `yaml:"severity"` // Severity replacing the default one of the rule
+	Vhosts   map[string]Severity `yaml:"vhosts"`   // Severity per vhost name, over Severity
+	Ignore   []string            `yaml:"ignore"`   // Name regular expressions the rule ignores
+}
+
+// Suppression accepts a known finding, typically until it is fixed.
+type Suppression struct {
+	Rule    string `yaml:"rule"`    // Name of the rule, any rule if empty
+	Vhost   string `yaml:"vhost"`   // Vhost of the object, any vhost if empty
+	Name    string `yaml:"name"`    // Name of the object
+	Expires Date   `yaml:"expires"` // Last day the suppression applies, never expires if zero
+	Reason  string `yaml:"reason"`  // Why the finding is accepted
+}
+
+// Date is a calendar day written as YYYY-MM-DD.
+type Date struct {
+	time.Time
+}
+
+// UnmarshalText decodes a YYYY-MM-DD date.
+func (d *Date) UnmarshalText(text []byte) error {
+	t, err := time.Parse(dateLayout, string(text))
+	if err != nil {
+		return fmt.Errorf("invalid date %q (expected YYYY-MM-DD)", text)
+	}
+	d.Time = t
+	return nil
+}
+
+// String returns the date as YYYY-MM-DD.
+func (d Date) String() string {
+	return d.Format(dateLayout)
+}
+
+// expired tells if the suppression no longer applies at now.
+func (s *Suppression) expired(now time.Time) bool {
+	return !s.Expires.IsZero() && !now.Before(s.Expires.AddDate(0, 0, 1))
+}
+
+// LoadConfig reads the configuration file at path, along with its suppressions file.
+func LoadConfig(path string) (*Config, error) {
+	var cfg Config
+	if err := decodeFile(path, &cfg); err != nil {
+		return nil, err
+	}
+
+	if cfg.SuppressionsFile != "" {
+		file := cfg.SuppressionsFile
+		if !filepath.IsAbs(file) {
+			file = filepath.Join(filepath.Dir(path), file)
+		}
+		var more struct {
+			Suppressions []Suppression `yaml:"suppressions"`
+		}
+		if err := decodeFile(file, &more); err != nil {
+			return nil, err
+		}
+		cfg.Suppressions = append(cfg.Suppressions, more.Suppressions...)
+	}
+
+	if err := cfg.validate(); err != nil {
+		return nil, fmt.Errorf("lint config %s: %w", path, err)
+	}
+	return &cfg, nil
+}
+
+// decodeFile decodes the YAML file at path into v.
+func decodeFile(path string, v any) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading lint config: %w", err)
+	}
+	if err := yaml.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("decoding lint config %s: %w", path, err)
+	}
+	return nil
+}
+
+// validate checks that rules exist and suppressions name an object.
+func (c *Config) validate() error {
+	known := make(map[string]bool)
+	for _, rule := range Rules(Options{}) {
+		known[rule.Name] = true
+	}
+
+	for name := range c.Rules {
+		if !known[name] {
+			return fmt.Errorf("unknown rule %q", name)
+		}
+	}
+	for i, s := range c.Suppressions {
+		switch {
+		case s.Name == "":
+			return fmt.Errorf("suppression %d: missing name", i+1)
+		case s.Rule != "" && !known[s.Rule]:
+			return fmt.Errorf("suppression %d: unknown rule %q", i+1, s.Rule)
+		}
+	}
+	return nil
+}
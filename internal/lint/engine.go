@@ -0,0 +1,134 @@
+package lint
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/Patrick-Ivann/AIM-Q/internal/rabbitmq"
+)
+
+// Engine runs the built-in rules as customized by a Config: disabled rules are
+// skipped, ignored names and suppressed findings are dropped and severities
+// are overridden. It is safe to reuse an engine across topologies.
+type Engine struct {
+	rules        []Rule
+	ignore       []*regexp.Regexp
+	ruleIgnore   map[string][]*regexp.Regexp
+	vhosts       map[string]map[string]Severity
+	suppressions []Suppression
+}
+
+// NewEngine compiles the configuration; a nil configuration runs the built-in
+// rules unchanged.
+func NewEngine(cfg *Config) (*Engine, error) {
+	if cfg == nil {
+		cfg = &Config{}
+	}
+
+	opts := Options{}
+	if cfg.ProductionVhosts != "" {
+		production, err := regexp.Compile(cfg.ProductionVhosts)
+		if err != nil {
+			return nil, fmt.Errorf("invalid production-vhosts: %w", err)
+		}
+		opts.ProductionVhosts = production
+	}
+
+	ignore, err := compileAll(cfg.Ignore)
+	if err != nil {
+		return nil, err
+	}
+	e := &Engine{
+		ignore:       ignore,
+		ruleIgnore:   make(map[string][]*regexp.Regexp),
+		vhosts:       make(map[string]map[string]Severity),
+		suppressions: cfg.Suppressions,
+	}
+
+	for _, rule := range Rules(opts) {
+		rc := cfg.Rules[rule.Name]
+		if rc.Enabled != nil && !*rc.Enabled {
+			continue
+		}
+		if rc.Severity != nil {
+			rule.Severity = *rc.Severity
+		}
+		if e.ruleIgnore[rule.Name], err = compileAll(rc.Ignore); err != nil {
+			return nil, fmt.Errorf("rule %s: %w", rule.Name, err)
+		}
+		e.vhosts[rule.Name] = rc.Vhosts
+		e.rules = append(e.rules, rule)
+	}
+	return e, nil
+}
+
+// compileAll compiles the name regular expressions of an ignore list.
+func compileAll(patterns []string) ([]*regexp.Regexp, error) {
+	res := make([]*regexp.Regexp, 0, len(patterns))
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ignore pattern: %w", err)
+		}
+		res = append(res, re)
+	}
+	return res, nil
+}
+
+// Run checks the topology against the enabled rules and applies the configuration to the findings.
+//
+// Suppressions past their expiry date no longer apply: their findings are
+// reported again, with the expiry date appended to the message.
+func (e *Engine) Run(topology *rabbitmq.Topology) *Report {
+	all := Run(topology, e.rules)
+	now := time.Now()
+
+	report := &Report{Findings: []Finding{}}
+	for _, f := range all.Findings {
+		if matchAny(e.ignore, f.Name) || matchAny(e.ruleIgnore[f.Rule], f.Name) {
+			continue
+		}
+		if severity, ok := e.vhosts[f.Rule][f.Vhost]; ok {
+			f.Severity = severity
+		}
+		if s := e.suppression(f, now); s != nil {
+			if !s.expired(now) {
+				report.Suppressed++
+				continue
+			}
+			f.Message += fmt.Sprintf(" (suppression expired on %s)", s.Expires)
+		}
+		report.Findings = append(report.Findings, f)
+	}
+
+	report.sort()
+	return report
+}
+
+// matchAny tells if a name matches one of the regular expressions.
+func matchAny(patterns []*regexp.Regexp, name string) bool {
+	for _, re := range patterns {
+		if re.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// suppression returns a suppression matching the finding, preferring one still
+// in force at now, nil if none.
+func (e *Engine) suppression(f Finding, now time.Time) *Suppression {
+	var expired *Suppression
+	for i := range e.suppressions {
+		s := &e.suppressions[i]
+		if s.Name != f.Name || (s.Rule != "" && s.Rule != f.Rule) || (s.Vhost != "" && s.Vhost != f.Vhost) {
+			continue
+		}
+		if !s.expired(now) {
+			return s
+		}
+		expired = s
+	}
+	return expired
+}
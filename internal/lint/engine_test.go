@@ -0,0 +1,143 @@
+package lint_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Patrick-Ivann/AIM-Q/internal/lint"
+	"github.com/Patrick-Ivann/AIM-Q/internal/rabbitmq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeConfig(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+func TestLoadConfig(t *testing.T) {
+	dir := t.TempDir()
+	writeConfig(t, dir, "suppressions.yaml", `
+suppressions:
+  - name: orphan
+    expires: 2000-01-01
+`)
+	path := writeConfig(t, dir, "lint.yaml", `
+production-vhosts: ^dev$
+ignore: [amq\.gen-]
+rules:
+  queue-without-consumers:
+    enabled: false
+  exchange-black-hole:
+    severity: error
+    vhosts:
+      prod: info
+suppressions:
+  - rule: dlx-missing-exchange
+    vhost: prod
+    name: dead-ends
+    reason: DLX created by the next release
+suppressions-file: suppressions.yaml
+`)
+
+	cfg, err := lint.LoadConfig(path)
+	require.NoError(t, err)
+	assert.Equal(t, "^dev$", cfg.ProductionVhosts)
+	assert.Equal(t, []string{`amq\.gen-`}, cfg.Ignore)
+	assert.False(t, *cfg.Rules["queue-without-consumers"].Enabled)
+	assert.Equal(t, lint.Error, *cfg.Rules["exchange-black-hole"].Severity)
+	assert.Equal(t, map[string]lint.Severity{"prod": lint.Info}, cfg.Rules["exchange-black-hole"].Vhosts)
+	require.Len(t, cfg.Suppressions, 2)
+	assert.Equal(t, "dead-ends", cfg.Suppressions[0].Name)
+	assert.True(t, cfg.Suppressions[0].Expires.IsZero())
+	assert.Equal(t, "2000-01-01", cfg.Suppressions[1].Expires.String())
+}
+
+func TestLoadConfig_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"rules:\n  no-such-rule: {enabled: false}\n":          `unknown rule "no-such-rule"`,
+		"rules:\n  exchange-black-hole: {severity: fatal}\n":  `unknown severity "fatal"`,
+		"suppressions:\n  - rule: exchange-black-hole\n":      "suppression 1: missing name",
+		"suppressions:\n  - {name: q, rule: typo}\n":          `suppression 1: unknown rule "typo"`,
+		"suppressions:\n  - {name: q, expires: 31/12/2026}\n": `invalid date "31/12/2026" (expected YYYY-MM-DD)`,
+		"suppressions-file: missing.yaml\n":                   "reading lint config",
+	}
+	for content, want := range tests {
+		t.Run(want, func(t *testing.T) {
+			_, err := lint.LoadConfig(writeConfig(t, t.TempDir(), "lint.yaml", content))
+			assert.ErrorContains(t, err, want)
+		})
+	}
+}
+
+func TestNewEngine_Invalid(t *testing.T) {
+	_, err := lint.NewEngine(&lint.Config{Ignore: []string{"("}})
+	assert.ErrorContains(t, err, "invalid ignore pattern")
+
+	_, err = lint.NewEngine(&lint.Config{ProductionVhosts: "("})
+	assert.ErrorContains(t, err, "invalid production-vhosts")
+}
+
+func TestEngine_Defaults(t *testing.T) {
+	engine, err := lint.NewEngine(nil)
+	require.NoError(t, err)
+	assert.Equal(t, lint.Run(topology(), lint.Rules(lint.Options{})), engine.Run(topology()))
+}
+
+func TestEngine_Run(t *testing.T) {
+	disabled := false
+	severity := lint.Info
+	engine, err := lint.NewEngine(&lint.Config{
+		ProductionVhosts: "^dev$",
+		Ignore:           []string{"-> deleted$"},
+		Rules: map[string]lint.RuleConfig{
+			"queue-without-consumers":     {Enabled: &disabled},
+			"queue-lifecycle-anomaly":     {Severity: &severity},
+			"binding-missing-destination": {Vhosts: map[string]lint.Severity{"dev": lint.Warning}},
+			"queue-without-bindings":      {Ignore: []string{"^orph"}},
+		},
+		Suppressions: []lint.Suppression{
+			{Rule: "dlx-missing-exchange", Vhost: "prod", Name: "dead-ends"},
+			{Name: "void", Expires: date(t, "2999-12-31")},
+			{Rule: "non-durable-production-queue", Name: "scratch", Expires: date(t, "2000-01-01")},
+		},
+	})
+	require.NoError(t, err)
+
+	report := engine.Run(topology())
+	assert.Equal(t, []string{
+		"binding-missing-destination dev/events -> scratch",
+		"non-durable-production-queue dev/scratch",
+		"queue-lifecycle-anomaly prod/sticky",
+	}, findings(report))
+	assert.Equal(t, lint.Warning, report.Findings[0].Severity)
+	assert.Equal(t, "non-durable queue in production vhost \"dev\" (suppression expired on 2000-01-01)",
+		report.Findings[1].Message)
+	assert.Equal(t, lint.Info, report.Findings[2].Severity)
+	assert.Equal(t, 2, report.Suppressed)
+
+	var buf bytes.Buffer
+	require.NoError(t, report.WriteText(&buf))
+	assert.Contains(t, buf.String(), "\n3 finding(s): 0 error(s), 2 warning(s), 1 info, 2 suppressed\n")
+}
+
+func TestEngine_Run_AllSuppressed(t *testing.T) {
+	topo := &rabbitmq.Topology{Queues: []rabbitmq.Queue{{Name: "jobs", Vhost: "/", Durable: true}}}
+	engine, err := lint.NewEngine(&lint.Config{Suppressions: []lint.Suppression{{Name: "jobs"}}})
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, engine.Run(topo).WriteText(&buf))
+	assert.Equal(t, "No findings, 2 suppressed.\n", buf.String())
+}
+
+func date(t *testing.T, s string) lint.Date {
+	t.Helper()
+	var d lint.Date
+	require.NoError(t, d.UnmarshalText([]byte(s)))
+	return d
+}
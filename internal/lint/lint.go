@@ -0,0 +1,121 @@
+// Package lint checks a RabbitMQ topology against rules detecting common
+// mistakes, such as queues nothing routes to or dead-letter exchanges that do
+// not exist, and reports findings with a severity.
+package lint
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Patrick-Ivann/AIM-Q/internal/rabbitmq"
+)
+
+// Severity ranks findings; higher severities are more serious.
+type Severity int
+
+// Severities of findings, in increasing order.
+const (
+	Info Severity = iota
+	Warning
+	Error
+)
+
+// severityNames are the names of severities in text, JSON and flags.
+var severityNames = []string{Info: "info", Warning: "warning", Error: "error"}
+
+// String returns the name of the severity.
+func (s Severity) String() string {
+	if s < Info || s > Error {
+		return fmt.Sprintf("Severity(%d)", int(s))
+	}
+	return severityNames[s]
+}
+
+// MarshalText encodes the severity as its name.
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText decodes a severity name.
+func (s *Severity) UnmarshalText(text []byte) error {
+	parsed, err := ParseSeverity(string(text))
+	if err != nil {
+		return err
+	}
+	*s = parsed
+	return nil
+}
+
+// ParseSeverity returns the severity with the given name.
+func ParseSeverity(name string) (Severity, error) {
+	if i := slices.Index(severityNames, strings.ToLower(name)); i >= 0 {
+		return Severity(i), nil
+	}
+	return Info, fmt.Errorf("unknown severity %q (expected %s)", name, strings.Join(severityNames, ", "))
+}
+
+// Finding reports an object breaking a rule.
+type Finding struct {
+	Rule     string   `json:"rule"`     // Name of the rule
+	Severity Severity `json:"severity"` // Severity of the rule
+	Kind     string   `json:"kind"`     // "exchange", "queue" or "binding"
+	Vhost    string   `json:"vhost"`    // Virtual host of the object
+	Name     string   `json:"name"`     // Name of the object; bindings are described as "source -> destination"
+	Message  string   `json:"message"`  // Description of the problem
+}
+
+// Rule is a check run over a topology.
+type Rule struct {
+	Name        string   // Identifier of the rule, used in reports and configuration
+	Description string   // What the rule detects
+	Severity    Severity // Severity of the findings of the rule
+	// Check returns the findings of the rule; Run sets their Rule and Severity.
+	Check func(topology *rabbitmq.Topology) []Finding
+}
+
+// Report holds the findings of a lint run, sorted by decreasing severity,
+// then by rule, vhost, kind and name.
+type Report struct {
+	Findings   []Finding `json:"findings"`
+	Suppressed int       `json:"suppressed,omitempty"` // Number of findings hidden by suppressions
+}
+
+// Run checks the topology against the rules.
+func Run(topology *rabbitmq.Topology, rules []Rule) *Report {
+	report := &Report{Findings: []Finding{}}
+	for _, rule := range rules {
+		for _, f := range rule.Check(topology) {
+			f.Rule, f.Severity = rule.Name, rule.Severity
+			report.Findings = append(report.Findings, f)
+		}
+	}
+
+	report.sort()
+	return report
+}
+
+// sort orders the findings as documented on Report.
+func (r *Report) sort() {
+	slices.SortStableFunc(r.Findings, func(a, b Finding) int {
+		return cmp.Or(
+			cmp.Compare(b.Severity, a.Severity),
+			cmp.Compare(a.Rule, b.Rule),
+			cmp.Compare(a.Vhost, b.Vhost),
+			cmp.Compare(a.Kind, b.Kind),
+			cmp.Compare(a.Name, b.Name),
+		)
+	})
+}
+
+// Count returns the number of findings at or above the threshold severity.
+func (r *Report) Count(threshold Severity) int {
+	n := 0
+	for _, f := range r.Findings {
+		if f.Severity >= threshold {
+			n++
+		}
+	}
+	return n
+}
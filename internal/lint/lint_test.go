@@ -0,0 +1,133 @@
+package lint_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"regexp"
+	"testing"
+
+	"github.com/Patrick-Ivann/AIM-Q/internal/lint"
+	"github.com/Patrick-Ivann/AIM-Q/internal/rabbitmq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// topology breaks every built-in rule once, except for the healthy "orders" queue.
+func topology() *rabbitmq.Topology {
+	return &rabbitmq.Topology{
+		Exchanges: []rabbitmq.Exchange{
+			{Name: "events", Vhost: "prod", Type: "topic"},
+			{Name: "void", Vhost: "prod", Type: "fanout"},
+			{Name: "routed-elsewhere", Vhost: "prod", Type: "fanout", Arguments: map[string]any{"alternate-exchange": "events"}},
+			{Name: "amq.topic", Vhost: "prod", Type: "topic"},
+		},
+		Queues: []rabbitmq.Queue{
+			{Name: "orders", Vhost: "prod", Durable: true},
+			{Name: "orphan", Vhost: "prod", Durable: true},
+			{Name: "cache", Vhost: "prod", Durable: false},
+			{Name: "scratch", Vhost: "dev", Durable: false},
+			{Name: "reply", Vhost: "prod", Exclusive: true},
+			{Name: "sticky", Vhost: "prod", Durable: true, AutoDelete: true},
+			{Name: "dead-ends", Vhost: "prod", Durable: true, Arguments: map[string]any{"x-dead-letter-exchange": "gone"}},
+		},
+		Bindings: []rabbitmq.Binding{
+			{Source: "events", Vhost: "prod", DestType: "queue", Destination: "orders"},
+			{Source: "events", Vhost: "prod", DestType: "queue", Destination: "cache"},
+			{Source: "events", Vhost: "prod", DestType: "queue", Destination: "sticky"},
+			{Source: "events", Vhost: "prod", DestType: "queue", Destination: "dead-ends"},
+			{Source: "events", Vhost: "prod", DestType: "queue", Destination: "deleted"},
+			{Source: "events", Vhost: "dev", DestType: "queue", Destination: "scratch"},
+		},
+		Consumers: []rabbitmq.Consumer{
+			{Queue: "orders", Vhost: "prod"}, {Queue: "orphan", Vhost: "prod"}, {Queue: "cache", Vhost: "prod"},
+			{Queue: "scratch", Vhost: "dev"}, {Queue: "reply", Vhost: "prod"}, {Queue: "sticky", Vhost: "prod"},
+		},
+	}
+}
+
+// findings returns the "rule vhost/name" of each finding.
+func findings(r *lint.Report) []string {
+	var out []string
+	for _, f := range r.Findings {
+		out = append(out, f.Rule+" "+f.Vhost+"/"+f.Name)
+	}
+	return out
+}
+
+func TestRun_BuiltInRules(t *testing.T) {
+	report := lint.Run(topology(), lint.Rules(lint.Options{}))
+
+	assert.Equal(t, []string{
+		"binding-missing-destination dev/events -> scratch",
+		"binding-missing-destination prod/events -> deleted",
+		"dlx-missing-exchange prod/dead-ends",
+		"exchange-black-hole prod/void",
+		"non-durable-production-queue prod/cache",
+		"queue-lifecycle-anomaly prod/sticky",
+		"queue-without-bindings prod/orphan",
+		"queue-without-consumers prod/dead-ends",
+	}, findings(report))
+	assert.Equal(t, lint.Error, report.Findings[0].Severity)
+	assert.Equal(t, `source exchange "events" does not exist`, report.Findings[0].Message)
+	assert.Equal(t, `destination queue "deleted" does not exist`, report.Findings[1].Message)
+	assert.Equal(t, 3, report.Count(lint.Error))
+	assert.Equal(t, 7, report.Count(lint.Warning))
+}
+
+func TestRun_ProductionVhosts(t *testing.T) {
+	rules := lint.Rules(lint.Options{ProductionVhosts: regexp.MustCompile(`^dev$`)})
+	report := lint.Run(topology(), rules)
+
+	assert.Contains(t, findings(report), "non-durable-production-queue dev/scratch")
+	assert.NotContains(t, findings(report), "non-durable-production-queue prod/cache")
+}
+
+func TestRun_DeadLetterExchangeFromPolicy(t *testing.T) {
+	topo := &rabbitmq.Topology{
+		Queues:   []rabbitmq.Queue{{Name: "jobs", Vhost: "/", Durable: true}},
+		Policies: []rabbitmq.Policy{{Name: "dlx", Vhost: "/", Pattern: ".*", Definition: map[string]any{"dead-letter-exchange": "jobs.dlx"}}},
+	}
+	topo.ApplyPolicies()
+
+	report := lint.Run(topo, lint.Rules(lint.Options{}))
+	assert.Contains(t, findings(report), "dlx-missing-exchange //jobs")
+}
+
+func TestParseSeverity(t *testing.T) {
+	s, err := lint.ParseSeverity("Warning")
+	require.NoError(t, err)
+	assert.Equal(t, lint.Warning, s)
+
+	_, err = lint.ParseSeverity("fatal")
+	assert.ErrorContains(t, err, `unknown severity "fatal" (expected info, warning, error)`)
+}
+
+func TestReport_WriteText(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, (&lint.Report{}).WriteText(&buf))
+	assert.Equal(t, "No findings.\n", buf.String())
+
+	buf.Reset()
+	report := lint.Run(topology(), lint.Rules(lint.Options{}))
+	require.NoError(t, report.WriteText(&buf))
+	assert.Contains(t, buf.String(), "SEVERITY  RULE ")
+	assert.Contains(t, buf.String(), "error     dlx-missing-exchange ")
+	assert.Contains(t, buf.String(), "\n8 finding(s): 3 error(s), 4 warning(s), 1 info\n")
+}
+
+func TestReport_WriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, lint.Run(topology(), lint.Rules(lint.Options{})).WriteJSON(&buf))
+
+	var doc struct {
+		Findings []lint.Finding `json:"findings"`
+	}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &doc))
+	require.Len(t, doc.Findings, 8)
+	assert.Contains(t, buf.String(), `"severity": "error"`)
+	assert.Equal(t, lint.Error, doc.Findings[0].Severity)
+
+	buf.Reset()
+	require.NoError(t, lint.Run(&rabbitmq.Topology{}, nil).WriteJSON(&buf))
+	assert.JSONEq(t, `{"findings": []}`, buf.String())
+}
@@ -0,0 +1,251 @@
+package lint
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Patrick-Ivann/AIM-Q/internal/rabbitmq"
+)
+
+// DefaultProductionVhosts matches the vhosts considered production by default.
+var DefaultProductionVhosts = regexp.MustCompile(`(?i)prod`)
+
+// Options configures the built-in rules.
+type Options struct {
+	// ProductionVhosts matches the vhosts where messages must survive a broker
+	// restart; nil means DefaultProductionVhosts.
+	ProductionVhosts *regexp.Regexp
+}
+
+// Rules returns the built-in rules.
+func Rules(opts Options) []Rule {
+	production := opts.ProductionVhosts
+	if production == nil {
+		production = DefaultProductionVhosts
+	}
+
+	return []Rule{
+		{
+			Name:        "queue-without-bindings",
+			Description: "Queue no exchange routes to, other than the default exchange",
+			Severity:    Warning,
+			Check:       queuesWithoutBindings,
+		},
+		{
+			Name:        "queue-without-consumers",
+			Description: "Queue nobody consumes from",
+			Severity:    Info,
+			Check:       queuesWithoutConsumers,
+		},
+		{
+			Name:        "exchange-black-hole",
+			Description: "Exchange without bindings nor alternate exchange, dropping every message",
+			Severity:    Warning,
+			Check:       blackHoleExchanges,
+		},
+		{
+			Name:        "binding-missing-destination",
+			Description: "Binding from or to an exchange or queue that does not exist",
+			Severity:    Error,
+			Check:       bindingsWithMissingEnds,
+		},
+		{
+			Name:        "dlx-missing-exchange",
+			Description: "Queue dead-lettering to an exchange that does not exist, losing rejected messages",
+			Severity:    Error,
+			Check:       missingDeadLetterExchanges,
+		},
+		{
+			Name:        "non-durable-production-queue",
+			Description: "Non-durable classic queue in a production vhost, lost on broker restart",
+			Severity:    Warning,
+			Check:       func(t *rabbitmq.Topology) []Finding { return nonDurableQueues(t, production) },
+		},
+		{
+			Name:        "queue-lifecycle-anomaly",
+			Description: "Durable queue that is also exclusive or auto-delete, and so will not outlive its users",
+			Severity:    Warning,
+			Check:       lifecycleAnomalies,
+		},
+	}
+}
+
+// objectKey identifies an exchange or queue across clusters and vhosts.
+func objectKey(cluster, vhost, name string) string {
+	return cluster + "\x00" + vhost + "\x00" + name
+}
+
+// exchangeSet returns the keys of the exchanges of the topology.
+func exchangeSet(t *rabbitmq.Topology) map[string]struct{} {
+	set := make(map[string]struct{}, len(t.Exchanges))
+	for _, ex := range t.Exchanges {
+		set[objectKey(ex.Cluster, ex.Vhost, ex.Name)] = struct{}{}
+	}
+	return set
+}
+
+// queueSet returns the keys of the queues of the topology.
+func queueSet(t *rabbitmq.Topology) map[string]struct{} {
+	set := make(map[string]struct{}, len(t.Queues))
+	for _, q := range t.Queues {
+		set[objectKey(q.Cluster, q.Vhost, q.Name)] = struct{}{}
+	}
+	return set
+}
+
+// exchangeExists tells if the named exchange is in the set; the default
+// exchange always exists, even when missing from definitions exports.
+func exchangeExists(exchanges map[string]struct{}, cluster, vhost, name string) bool {
+	if name == "" {
+		return true
+	}
+	_, ok := exchanges[objectKey(cluster, vhost, name)]
+	return ok
+}
+
+// builtIn tells if an exchange is created by RabbitMQ itself.
+func builtIn(name string) bool {
+	return name == "" || strings.HasPrefix(name, "amq.")
+}
+
+// queueFinding returns a finding about a queue.
+func queueFinding(q rabbitmq.Queue, format string, args ...any) Finding {
+	return Finding{Kind: "queue", Vhost: q.Vhost, Name: q.Name, Message: fmt.Sprintf(format, args...)}
+}
+
+// queuesWithoutBindings reports queues no exchange other than the default one
+// routes to. Exclusive queues, typically RPC reply queues reached through the
+// default exchange, are ignored.
+func queuesWithoutBindings(t *rabbitmq.Topology) []Finding {
+	bound := make(map[string]struct{})
+	for _, b := range t.Bindings {
+		if b.DestType == "queue" && b.Source != "" {
+			bound[objectKey(b.Cluster, b.Vhost, b.Destination)] = struct{}{}
+		}
+	}
+
+	var findings []Finding
+	for _, q := range t.Queues {
+		if _, ok := bound[objectKey(q.Cluster, q.Vhost, q.Name)]; !ok && !q.Exclusive {
+			findings = append(findings, queueFinding(q, "no exchange is bound to the queue"))
+		}
+	}
+	return findings
+}
+
+// queuesWithoutConsumers reports queues without consumers.
+func queuesWithoutConsumers(t *rabbitmq.Topology) []Finding {
+	consumed := make(map[string]struct{})
+	for _, c := range t.Consumers {
+		consumed[objectKey(c.Cluster, c.Vhost, c.Queue)] = struct{}{}
+	}
+
+	var findings []Finding
+	for _, q := range t.Queues {
+		if _, ok := consumed[objectKey(q.Cluster, q.Vhost, q.Name)]; !ok {
+			findings = append(findings, queueFinding(q, "the queue has no consumer (%d messages)", q.MessageStats.Messages))
+		}
+	}
+	return findings
+}
+
+// blackHoleExchanges reports exchanges that route nowhere: they have no
+// bindings and no alternate exchange. Built-in exchanges are ignored.
+func blackHoleExchanges(t *rabbitmq.Topology) []Finding {
+	sources := make(map[string]struct{})
+	for _, b := range t.Bindings {
+		sources[objectKey(b.Cluster, b.Vhost, b.Source)] = struct{}{}
+	}
+
+	var findings []Finding
+	for _, ex := range t.Exchanges {
+		if builtIn(ex.Name) {
+			continue
+		}
+		if _, ok := sources[objectKey(ex.Cluster, ex.Vhost, ex.Name)]; ok {
+			continue
+		}
+		if _, ok := ex.Effective("alternate-exchange"); ok {
+			continue
+		}
+		findings = append(findings, Finding{
+			Kind: "exchange", Vhost: ex.Vhost, Name: ex.Name,
+			Message: "the exchange has no bindings nor alternate exchange: published messages are dropped",
+		})
+	}
+	return findings
+}
+
+// bindingsWithMissingEnds reports bindings whose source exchange or destination does not exist.
+func bindingsWithMissingEnds(t *rabbitmq.Topology) []Finding {
+	exchanges, queues := exchangeSet(t), queueSet(t)
+
+	var findings []Finding
+	for _, b := range t.Bindings {
+		var missing []string
+		if !exchangeExists(exchanges, b.Cluster, b.Vhost, b.Source) {
+			missing = append(missing, fmt.Sprintf("source exchange %q", b.Source))
+		}
+		destinations := queues
+		if b.DestType == "exchange" {
+			destinations = exchanges
+		}
+		if _, ok := destinations[objectKey(b.Cluster, b.Vhost, b.Destination)]; !ok {
+			missing = append(missing, fmt.Sprintf("destination %s %q", b.DestType, b.Destination))
+		}
+		if len(missing) > 0 {
+			findings = append(findings, Finding{
+				Kind: "binding", Vhost: b.Vhost, Name: b.Source + " -> " + b.Destination,
+				Message: strings.Join(missing, " and ") + " does not exist",
+			})
+		}
+	}
+	return findings
+}
+
+// missingDeadLetterExchanges reports queues whose effective dead-letter exchange does not exist.
+func missingDeadLetterExchanges(t *rabbitmq.Topology) []Finding {
+	exchanges := exchangeSet(t)
+
+	var findings []Finding
+	for _, q := range t.Queues {
+		dlx, ok := q.Effective("dead-letter-exchange")
+		if !ok {
+			continue
+		}
+		if name := fmt.Sprint(dlx); !exchangeExists(exchanges, q.Cluster, q.Vhost, name) {
+			findings = append(findings, queueFinding(q, "dead-letter exchange %q does not exist", name))
+		}
+	}
+	return findings
+}
+
+// nonDurableQueues reports non-durable classic queues of production vhosts.
+// Exclusive queues are transient by design and ignored.
+func nonDurableQueues(t *rabbitmq.Topology, production *regexp.Regexp) []Finding {
+	var findings []Finding
+	for _, q := range t.Queues {
+		if q.Durable || q.Exclusive || q.QueueType() != "classic" || !production.MatchString(q.Vhost) {
+			continue
+		}
+		findings = append(findings, queueFinding(q, "non-durable queue in production vhost %q", q.Vhost))
+	}
+	return findings
+}
+
+// lifecycleAnomalies reports durable queues that are exclusive or auto-delete,
+// whose durability is defeated by their deletion with the connection or the
+// last consumer.
+func lifecycleAnomalies(t *rabbitmq.Topology) []Finding {
+	var findings []Finding
+	for _, q := range t.Queues {
+		switch {
+		case q.Durable && q.Exclusive:
+			findings = append(findings, queueFinding(q, "durable queue is exclusive: it is deleted with its connection"))
+		case q.Durable && q.AutoDelete:
+			findings = append(findings, queueFinding(q, "durable queue is auto-delete: it is deleted with its last consumer"))
+		}
+	}
+	return findings
+}
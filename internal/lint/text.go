@@ -0,0 +1,42 @@
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+)
+
+// WriteText writes the findings as an aligned table followed by a summary line.
+func (r *Report) WriteText(w io.Writer) error {
+	suppressed := ""
+	if r.Suppressed > 0 {
+		suppressed = fmt.Sprintf(", %d suppressed", r.Suppressed)
+	}
+	if len(r.Findings) == 0 {
+		_, err := fmt.Fprintf(w, "No findings%s.\n", suppressed)
+		return err
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "SEVERITY\tRULE\tVHOST\tOBJECT\tMESSAGE")
+	for _, f := range r.Findings {
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s %s\t%s\n", f.Severity, f.Rule, f.Vhost, f.Kind, f.Name, f.Message)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	numErrors, numWarnings := r.Count(Error), r.Count(Warning)-r.Count(Error)
+	_, err := fmt.Fprintf(w, "\n%d finding(s): %d error(s), %d warning(s), %d info%s\n",
+		len(r.Findings), numErrors, numWarnings, len(r.Findings)-numErrors-numWarnings, suppressed)
+	return err
+}
+
+// WriteJSON writes the report as an indented JSON document of the form
+// {"findings": [{rule, severity, kind, vhost, name, message}]}.
+func (r *Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
@@ -1,14 +1,37 @@
 package logger
 
 import (
+	"io"
 	"log/slog"
 	"os"
+
+	"github.com/Patrick-Ivann/AIM-Q/internal/redact"
 )
 
 // New returns a configured slog.Logger with text output and INFO level.
+//
+// Credentials embedded in URLs are scrubbed from every message and attribute.
 func New() *slog.Logger {
-	handler := slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
-		Level: slog.LevelInfo,
+	return NewWriter(os.Stdout)
+}
+
+// NewWriter is New writing to w, such as stderr for commands printing their
+// result to stdout, so that the result can be piped.
+func NewWriter(w io.Writer) *slog.Logger {
+	handler := slog.NewTextHandler(w, &slog.HandlerOptions{
+		Level:       slog.LevelInfo,
+		ReplaceAttr: scrubAttr,
 	})
 	return slog.New(handler)
-}
\ No newline at end of file
+}
+
+// scrubAttr redacts credentials from string and error attribute values.
+func scrubAttr(_ []string, a slog.Attr) slog.Attr {
+	switch v := a.Value.Any().(type) {
+	case string:
+		a.Value = slog.StringValue(redact.String(v))
+	case error:
+		a.Value = slog.StringValue(redact.String(v.Error()))
+	}
+	return a
+}
@@ -0,0 +1,189 @@
+package rabbitmq
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Authenticator adds credentials to requests sent to the management API.
+//
+// Implementations must be safe for concurrent use, as FetchTopologyContext
+// authenticates its requests concurrently.
+type Authenticator interface {
+	// Authenticate sets the credentials of req, typically its Authorization header.
+	Authenticate(req *http.Request) error
+}
+
+// invalidator is implemented by authenticators caching credentials that the
+// broker may reject before they expire, such as revoked OAuth2 tokens.
+type invalidator interface {
+	// Invalidate discards the cached credentials after a 401 response.
+	Invalidate()
+}
+
+// BasicAuth authenticates requests with HTTP basic authentication.
+//
+// It is the authenticator of clients created by NewClient from a URI with user info.
+type BasicAuth struct {
+	Username string
+	Password string
+}
+
+// Authenticate implements Authenticator.
+func (a BasicAuth) Authenticate(req *http.Request) error {
+	req.SetBasicAuth(a.Username, a.Password)
+	return nil
+}
+
+// BearerToken authenticates requests with a static OAuth2 access token.
+type BearerToken string
+
+// Authenticate implements Authenticator.
+func (t BearerToken) Authenticate(req *http.Request) error {
+	req.Header.Set("Authorization", "Bearer "+string(t))
+	return nil
+}
+
+// TokenFile authenticates requests with an OAuth2 access token read from a file.
+//
+// The file is read for every request, so tokens rotated by an external agent
+// (for example a Kubernetes projected volume) are picked up without restarting.
+type TokenFile struct {
+	Path string
+}
+
+// Authenticate implements Authenticator.
+func (f TokenFile) Authenticate(req *http.Request) error {
+	token, err := os.ReadFile(f.Path)
+	if err != nil {
+		return fmt.Errorf("reading token file: %w", err)
+	}
+	trimmed := strings.TrimSpace(string(token))
+	if trimmed == "" {
+		return fmt.Errorf("token file %s is empty", f.Path)
+	}
+	return BearerToken(trimmed).Authenticate(req)
+}
+
+// tokenExpiryMargin is how long before its expiry a cached token is renewed,
+// so it does not expire while a request is in flight. Tokens valid for less than
+// twice the margin are renewed halfway through their lifetime instead.
+const tokenExpiryMargin = 30 * time.Second
+
+// ClientCredentials authenticates requests with access tokens obtained from an
+// OAuth2 token endpoint using the client credentials grant, as expected by the
+// rabbitmq_auth_backend_oauth2 plugin.
+//
+// Tokens are cached and renewed shortly before they expire, or after the broker
+// rejects one, so long-running sessions such as the TUI keep working.
+type ClientCredentials struct {
+	TokenURL     string     // Token endpoint of the authorization server
+	ClientID     string     // OAuth2 client identifier
+	ClientSecret string     // OAuth2 client secret
+	Scopes       []string   // Requested scopes, e.g. "rabbitmq.tag:monitoring"
+	Http         HTTPClient // HTTP client used for the token endpoint; nil means http.DefaultClient
+
+	mu     sync.Mutex
+	token  string
+	expiry time.Time // Zero if the token does not expire
+}
+
+// tokenResponse is the successful response of an OAuth2 token endpoint (RFC 6749, section 5.1).
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int    `json:"expires_in"`
+}
+
+// Authenticate implements Authenticator, requesting a new token if needed.
+func (c *ClientCredentials) Authenticate(req *http.Request) error {
+	token, err := c.Token(req.Context())
+	if err != nil {
+		return err
+	}
+	return BearerToken(token).Authenticate(req)
+}
+
+// Invalidate discards the cached token so the next request obtains a new one.
+func (c *ClientCredentials) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.token = ""
+}
+
+// Token returns a valid access token, requesting one from the token endpoint
+// if none is cached or the cached one is about to expire.
+func (c *ClientCredentials) Token(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.token != "" && (c.expiry.IsZero() || time.Now().Before(c.expiry)) {
+		return c.token, nil
+	}
+
+	resp, err := c.requestToken(ctx)
+	if err != nil {
+		return "", err
+	}
+	c.token = resp.AccessToken
+	c.expiry = time.Time{}
+	if resp.ExpiresIn > 0 {
+		lifetime := time.Duration(resp.ExpiresIn) * time.Second
+		c.expiry = time.Now().Add(lifetime - min(tokenExpiryMargin, lifetime/2))
+	}
+	return c.token, nil
+}
+
+// requestToken performs a client credentials grant against the token endpoint.
+func (c *ClientCredentials) requestToken(ctx context.Context) (*tokenResponse, error) {
+	form := url.Values{
+		"grant_type":    {"client_credentials"},
+		"client_id":     {c.ClientID},
+		"client_secret": {c.ClientSecret},
+	}
+	if len(c.Scopes) > 0 {
+		form.Set("scope", strings.Join(c.Scopes, " "))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	httpClient := c.Http
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("token request failed: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("token endpoint returned HTTP %d: %s", resp.StatusCode, string(body))
+	}
+
+	var token tokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return nil, fmt.Errorf("decoding token response: %w", err)
+	}
+	if token.AccessToken == "" {
+		return nil, errors.New("token endpoint returned no access_token")
+	}
+	return &token, nil
+}
@@ -0,0 +1,176 @@
+package rabbitmq_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Patrick-Ivann/AIM-Q/internal/rabbitmq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// authorizationServer returns a management API stub recording the Authorization
+// header of the last request, answering 401 when it differs from accept.
+func authorizationServer(t *testing.T, accept func(string) bool) (*httptest.Server, *atomic.Value) {
+	t.Helper()
+	var last atomic.Value
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := r.Header.Get("Authorization")
+		last.Store(header)
+		if !accept(header) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = io.WriteString(w, "[]")
+	}))
+	t.Cleanup(server.Close)
+	return server, &last
+}
+
+// tokenEndpoint returns an OAuth2 token endpoint stub issuing "token-1", "token-2"...
+// valid for expiresIn seconds, and the number of tokens issued.
+func tokenEndpoint(t *testing.T, expiresIn int) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var issued atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "client_credentials", r.FormValue("grant_type"))
+		if r.FormValue("client_id") != "aimq" || r.FormValue("client_secret") != "s3cret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = io.WriteString(w, `{"error":"invalid_client"}`)
+			return
+		}
+		assert.Equal(t, "rabbitmq.read:*/* rabbitmq.tag:monitoring", r.FormValue("scope"))
+		_, _ = fmt.Fprintf(w, `{"access_token":"token-%d","token_type":"bearer","expires_in":%d}`,
+			issued.Add(1), expiresIn)
+	}))
+	t.Cleanup(server.Close)
+	return server, &issued
+}
+
+func clientCredentials(tokenURL, secret string) *rabbitmq.ClientCredentials {
+	return &rabbitmq.ClientCredentials{
+		TokenURL:     tokenURL,
+		ClientID:     "aimq",
+		ClientSecret: secret,
+		Scopes:       []string{"rabbitmq.read:*/*", "rabbitmq.tag:monitoring"},
+	}
+}
+
+func TestNewClient_BasicAuthFromURI(t *testing.T) {
+	server, last := authorizationServer(t, func(string) bool { return true })
+
+	client, err := rabbitmq.NewClient("http://guest:p%40ss@"+server.Listener.Addr().String(), server.Client())
+	require.NoError(t, err)
+	assert.Equal(t, rabbitmq.BasicAuth{Username: "guest", Password: "p@ss"}, client.Auth)
+
+	var out []any
+	require.NoError(t, client.Get("queues", &out))
+	assert.Equal(t, "Basic Z3Vlc3Q6cEBzcw==", last.Load())
+}
+
+func TestBearerToken(t *testing.T) {
+	server, last := authorizationServer(t, func(h string) bool { return h == "Bearer abc" })
+
+	client, err := rabbitmq.NewClient(server.URL, server.Client())
+	require.NoError(t, err)
+	client.Auth = rabbitmq.BearerToken("abc")
+
+	var out []any
+	require.NoError(t, client.Get("queues", &out))
+	assert.Equal(t, "Bearer abc", last.Load())
+}
+
+func TestTokenFile_RereadsRotatedToken(t *testing.T) {
+	server, last := authorizationServer(t, func(string) bool { return true })
+	path := filepath.Join(t.TempDir(), "token")
+
+	client, err := rabbitmq.NewClient(server.URL, server.Client())
+	require.NoError(t, err)
+	client.Auth = rabbitmq.TokenFile{Path: path}
+
+	var out []any
+	assert.ErrorContains(t, client.Get("queues", &out), "reading token file")
+
+	require.NoError(t, os.WriteFile(path, []byte("first\n"), 0600))
+	require.NoError(t, client.Get("queues", &out))
+	assert.Equal(t, "Bearer first", last.Load())
+
+	require.NoError(t, os.WriteFile(path, []byte("second\n"), 0600))
+	require.NoError(t, client.Get("queues", &out))
+	assert.Equal(t, "Bearer second", last.Load())
+}
+
+func TestClientCredentials_CachesToken(t *testing.T) {
+	tokens, issued := tokenEndpoint(t, 3600)
+	server, last := authorizationServer(t, func(string) bool { return true })
+
+	client, err := rabbitmq.NewClient(server.URL, server.Client())
+	require.NoError(t, err)
+	client.Auth = clientCredentials(tokens.URL, "s3cret")
+
+	_, err = client.FetchTopology()
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer token-1", last.Load())
+	assert.Equal(t, int32(1), issued.Load(), "concurrent requests must share the token")
+}
+
+func TestClientCredentials_CachesShortLivedToken(t *testing.T) {
+	// Tokens valid for less than the expiry margin are still cached for half their lifetime.
+	tokens, issued := tokenEndpoint(t, 20)
+	auth := clientCredentials(tokens.URL, "s3cret")
+
+	first, err := auth.Token(context.Background())
+	require.NoError(t, err)
+	second, err := auth.Token(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, "token-1", first)
+	assert.Equal(t, "token-1", second)
+	assert.Equal(t, int32(1), issued.Load())
+}
+
+func TestClientCredentials_RefreshesExpiredToken(t *testing.T) {
+	tokens, issued := tokenEndpoint(t, 1)
+	auth := clientCredentials(tokens.URL, "s3cret")
+
+	first, err := auth.Token(context.Background())
+	require.NoError(t, err)
+	time.Sleep(600 * time.Millisecond)
+	second, err := auth.Token(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, "token-1", first)
+	assert.Equal(t, "token-2", second)
+	assert.Equal(t, int32(2), issued.Load())
+}
+
+func TestClientCredentials_RenewsRejectedToken(t *testing.T) {
+	tokens, _ := tokenEndpoint(t, 3600)
+	server, last := authorizationServer(t, func(h string) bool { return h != "Bearer token-1" })
+
+	client, err := rabbitmq.NewClient(server.URL, server.Client())
+	require.NoError(t, err)
+	client.Auth = clientCredentials(tokens.URL, "s3cret")
+
+	var out []any
+	assert.ErrorAs(t, client.Get("queues", &out), new(*rabbitmq.UnauthorizedError))
+	require.NoError(t, client.Get("queues", &out))
+	assert.Equal(t, "Bearer token-2", last.Load())
+}
+
+func TestClientCredentials_InvalidClient(t *testing.T) {
+	tokens, _ := tokenEndpoint(t, 3600)
+
+	_, err := clientCredentials(tokens.URL, "wrong").Token(context.Background())
+	assert.ErrorContains(t, err, "token endpoint returned HTTP 401")
+	assert.ErrorContains(t, err, "invalid_client")
+}
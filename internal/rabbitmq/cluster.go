@@ -0,0 +1,148 @@
+package rabbitmq
+
+import (
+	"slices"
+	"strings"
+)
+
+// SetCluster tags every object of the topology with the name of the cluster it
+// was fetched from, and records the host names of the brokers of the cluster, so
+// that shovels and federation links pointing to them can be resolved.
+func (t *Topology) SetCluster(name string, hosts ...string) {
+	if len(hosts) > 0 {
+		t.ClusterHosts = map[string][]string{name: hosts}
+	}
+	for i := range t.Exchanges {
+		t.Exchanges[i].Cluster = name
+	}
+	for i := range t.Queues {
+		t.Queues[i].Cluster = name
+	}
+	for i := range t.Bindings {
+		t.Bindings[i].Cluster = name
+	}
+	for i := range t.Consumers {
+		t.Consumers[i].Cluster = name
+	}
+	for i := range t.Policies {
+		t.Policies[i].Cluster = name
+	}
+	for i := range t.OperatorPolicies {
+		t.OperatorPolicies[i].Cluster = name
+	}
+	for i := range t.Shovels {
+		t.Shovels[i].Cluster = name
+	}
+	for i := range t.FederationLinks {
+		t.FederationLinks[i].Cluster = name
+	}
+}
+
+// Merge returns a topology holding the objects of all the given topologies,
+// typically fetched from different clusters and tagged with SetCluster.
+func Merge(topologies ...*Topology) *Topology {
+	merged := &Topology{}
+	for _, t := range topologies {
+		merged.Exchanges = append(merged.Exchanges, t.Exchanges...)
+		merged.Queues = append(merged.Queues, t.Queues...)
+		merged.Bindings = append(merged.Bindings, t.Bindings...)
+		merged.Consumers = append(merged.Consumers, t.Consumers...)
+		merged.Policies = append(merged.Policies, t.Policies...)
+		merged.OperatorPolicies = append(merged.OperatorPolicies, t.OperatorPolicies...)
+		merged.Shovels = append(merged.Shovels, t.Shovels...)
+		merged.FederationLinks = append(merged.FederationLinks, t.FederationLinks...)
+		for name, hosts := range t.ClusterHosts {
+			if merged.ClusterHosts == nil {
+				merged.ClusterHosts = make(map[string][]string)
+			}
+			merged.ClusterHosts[name] = append(merged.ClusterHosts[name], hosts...)
+		}
+	}
+	return merged
+}
+
+// Clusters returns the sorted names of the clusters the objects of the topology
+// belong to. Topologies fetched from a single broker have no cluster tag, so the
+// result is then a single empty name.
+func (t *Topology) Clusters() []string {
+	var names []string
+	for _, ex := range t.Exchanges {
+		names = append(names, ex.Cluster)
+	}
+	for _, q := range t.Queues {
+		names = append(names, q.Cluster)
+	}
+	slices.Sort(names)
+	names = slices.Compact(names)
+	if len(names) == 0 {
+		return []string{""}
+	}
+	return names
+}
+
+// HostCluster returns the name of the merged cluster a broker host name belongs
+// to, or false if the host is not one of the merged clusters. Host names are
+// compared case-insensitively.
+func (t *Topology) HostCluster(host string) (string, bool) {
+	for name, hosts := range t.ClusterHosts {
+		for _, h := range hosts {
+			if strings.EqualFold(h, host) {
+				return name, true
+			}
+		}
+	}
+	return "", false
+}
+
+// InCluster returns the part of the topology belonging to the named cluster.
+//
+// It returns t itself when all objects belong to that cluster.
+func (t *Topology) InCluster(name string) *Topology {
+	if clusters := t.Clusters(); len(clusters) == 1 && clusters[0] == name {
+		return t
+	}
+	inCluster := func(cluster string) bool { return cluster == name }
+
+	sub := &Topology{}
+	for _, ex := range t.Exchanges {
+		if inCluster(ex.Cluster) {
+			sub.Exchanges = append(sub.Exchanges, ex)
+		}
+	}
+	for _, q := range t.Queues {
+		if inCluster(q.Cluster) {
+			sub.Queues = append(sub.Queues, q)
+		}
+	}
+	for _, b := range t.Bindings {
+		if inCluster(b.Cluster) {
+			sub.Bindings = append(sub.Bindings, b)
+		}
+	}
+	for _, c := range t.Consumers {
+		if inCluster(c.Cluster) {
+			sub.Consumers = append(sub.Consumers, c)
+		}
+	}
+	for _, p := range t.Policies {
+		if inCluster(p.Cluster) {
+			sub.Policies = append(sub.Policies, p)
+		}
+	}
+	for _, p := range t.OperatorPolicies {
+		if inCluster(p.Cluster) {
+			sub.OperatorPolicies = append(sub.OperatorPolicies, p)
+		}
+	}
+	for _, s := range t.Shovels {
+		if inCluster(s.Cluster) {
+			sub.Shovels = append(sub.Shovels, s)
+		}
+	}
+	for _, l := range t.FederationLinks {
+		if inCluster(l.Cluster) {
+			sub.FederationLinks = append(sub.FederationLinks, l)
+		}
+	}
+	return sub
+}
@@ -0,0 +1,55 @@
+package rabbitmq_test
+
+import (
+	"testing"
+
+	"github.com/Patrick-Ivann/AIM-Q/internal/rabbitmq"
+	"github.com/stretchr/testify/assert"
+)
+
+func clusterTopology(name string) *rabbitmq.Topology {
+	topology := &rabbitmq.Topology{
+		Exchanges: []rabbitmq.Exchange{{Name: "orders", Vhost: "/", Type: "topic"}},
+		Queues:    []rabbitmq.Queue{{Name: "order.created", Vhost: "/"}},
+		Bindings:  []rabbitmq.Binding{{Source: "orders", Destination: "order.created", DestType: "queue", Vhost: "/"}},
+		Consumers: []rabbitmq.Consumer{{Queue: "order.created", Vhost: "/", ConsumerTag: "ctag"}},
+		Policies:  []rabbitmq.Policy{{Name: "ha", Vhost: "/"}},
+	}
+	topology.SetCluster(name)
+	return topology
+}
+
+func TestTopology_SetCluster(t *testing.T) {
+	topology := clusterTopology("eu")
+
+	assert.Equal(t, "eu", topology.Exchanges[0].Cluster)
+	assert.Equal(t, "eu", topology.Queues[0].Cluster)
+	assert.Equal(t, "eu", topology.Bindings[0].Cluster)
+	assert.Equal(t, "eu", topology.Consumers[0].Cluster)
+	assert.Equal(t, "eu", topology.Policies[0].Cluster)
+}
+
+func TestMerge(t *testing.T) {
+	merged := rabbitmq.Merge(clusterTopology("us"), clusterTopology("eu"))
+
+	assert.Len(t, merged.Exchanges, 2)
+	assert.Len(t, merged.Queues, 2)
+	assert.Len(t, merged.Bindings, 2)
+	assert.Len(t, merged.Consumers, 2)
+	assert.Len(t, merged.Policies, 2)
+	assert.Equal(t, []string{"eu", "us"}, merged.Clusters())
+
+	eu := merged.InCluster("eu")
+	assert.Equal(t, clusterTopology("eu"), eu)
+
+	merged.Sort()
+	assert.Equal(t, "eu", merged.Exchanges[0].Cluster)
+}
+
+func TestTopology_Clusters_Untagged(t *testing.T) {
+	topology := clusterTopology("")
+
+	assert.Equal(t, []string{""}, topology.Clusters())
+	assert.Same(t, topology, topology.InCluster(""))
+	assert.Equal(t, []string{""}, (&rabbitmq.Topology{}).Clusters())
+}
@@ -0,0 +1,85 @@
+package rabbitmq
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+// DefaultVhost is assigned to objects of single-vhost definition exports, which omit the vhost.
+const DefaultVhost = "/"
+
+// Definitions mirrors the JSON document produced by a RabbitMQ definitions export
+// (GET /api/definitions, "Export definitions" in the management UI or
+// rabbitmqctl export_definitions).
+//
+// Users, permissions and parameters are ignored as they do not affect the topology.
+type Definitions struct {
+	RabbitVersion string `json:"rabbit_version"` // Version of the broker that produced the export
+	Vhosts        []struct {
+		Name string `json:"name"` // Virtual host name
+	} `json:"vhosts"`
+	Exchanges []Exchange `json:"exchanges"`
+	Queues    []Queue    `json:"queues"`
+	Bindings  []Binding  `json:"bindings"`
+	Policies  []Policy   `json:"policies"`
+}
+
+// LoadDefinitions reads a definitions export file and converts it to a Topology.
+func LoadDefinitions(path string) (*Topology, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening definitions: %w", err)
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+	return ParseDefinitions(f)
+}
+
+// ParseDefinitions decodes a definitions export and converts it to a Topology.
+//
+// Definitions carry no runtime state, so the resulting Topology has no consumers
+// and zero message statistics. Objects without a vhost (single-vhost exports) are
+// assigned DefaultVhost.
+func ParseDefinitions(r io.Reader) (*Topology, error) {
+	var defs Definitions
+	if err := json.NewDecoder(r).Decode(&defs); err != nil {
+		return nil, fmt.Errorf("decoding definitions: %w", err)
+	}
+	return defs.Topology(), nil
+}
+
+// Topology converts the definitions to a Topology.
+func (d *Definitions) Topology() *Topology {
+	topology := &Topology{
+		Exchanges: d.Exchanges,
+		Queues:    d.Queues,
+		Bindings:  d.Bindings,
+		Policies:  d.Policies,
+	}
+
+	for i := range topology.Exchanges {
+		topology.Exchanges[i].Vhost = orDefaultVhost(topology.Exchanges[i].Vhost)
+	}
+	for i := range topology.Queues {
+		topology.Queues[i].Vhost = orDefaultVhost(topology.Queues[i].Vhost)
+	}
+	for i := range topology.Bindings {
+		topology.Bindings[i].Vhost = orDefaultVhost(topology.Bindings[i].Vhost)
+	}
+	for i := range topology.Policies {
+		topology.Policies[i].Vhost = orDefaultVhost(topology.Policies[i].Vhost)
+	}
+	topology.ApplyPolicies()
+	return topology
+}
+
+// orDefaultVhost returns vhost, or DefaultVhost when it is empty.
+func orDefaultVhost(vhost string) string {
+	if vhost == "" {
+		return DefaultVhost
+	}
+	return vhost
+}
@@ -0,0 +1,58 @@
+package rabbitmq_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Patrick-Ivann/AIM-Q/internal/rabbitmq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadDefinitions(t *testing.T) {
+	topo, err := rabbitmq.LoadDefinitions("testdata/definitions.json")
+	require.NoError(t, err)
+
+	require.Len(t, topo.Exchanges, 2)
+	assert.Equal(t, "orders", topo.Exchanges[0].Name)
+	assert.Equal(t, "topic", topo.Exchanges[0].Type)
+	assert.True(t, topo.Exchanges[0].Durable)
+
+	require.Len(t, topo.Queues, 2)
+	assert.Equal(t, "quorum", topo.Queues[0].Arguments["x-queue-type"])
+
+	require.Len(t, topo.Bindings, 2)
+	assert.Equal(t, "order.created.*", topo.Bindings[0].RoutingKey)
+	assert.Equal(t, "queue", topo.Bindings[0].DestType)
+
+	require.Len(t, topo.Policies, 1)
+	assert.Equal(t, "queues", topo.Policies[0].ApplyTo)
+	assert.Equal(t, "orders.dlx", topo.Policies[0].Definition["dead-letter-exchange"])
+
+	assert.Empty(t, topo.Consumers)
+}
+
+func TestLoadDefinitions_MissingFile(t *testing.T) {
+	_, err := rabbitmq.LoadDefinitions("testdata/does-not-exist.json")
+	assert.ErrorContains(t, err, "opening definitions")
+}
+
+func TestParseDefinitions_SingleVhostExport(t *testing.T) {
+	input := `{
+		"exchanges": [{"name": "ex1", "type": "direct"}],
+		"queues": [{"name": "q1"}],
+		"bindings": [{"source": "ex1", "destination": "q1", "destination_type": "queue"}]
+	}`
+
+	topo, err := rabbitmq.ParseDefinitions(strings.NewReader(input))
+	require.NoError(t, err)
+
+	assert.Equal(t, rabbitmq.DefaultVhost, topo.Exchanges[0].Vhost)
+	assert.Equal(t, rabbitmq.DefaultVhost, topo.Queues[0].Vhost)
+	assert.Equal(t, rabbitmq.DefaultVhost, topo.Bindings[0].Vhost)
+}
+
+func TestParseDefinitions_InvalidJSON(t *testing.T) {
+	_, err := rabbitmq.ParseDefinitions(strings.NewReader("{not json"))
+	assert.ErrorContains(t, err, "decoding definitions")
+}
@@ -0,0 +1,78 @@
+package rabbitmq
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// APIError describes a non-200 response of the management API.
+//
+// GetContext returns one of the more specific types below when the status code
+// has a well-known meaning; all of them embed APIError, so callers can inspect
+// them with errors.As:
+//
+//	var notFound *rabbitmq.NotFoundError
+//	if errors.As(err, &notFound) { ... }
+type APIError struct {
+	StatusCode int    // HTTP status code of the response
+	Path       string // API path that was requested, relative to /api/
+	Body       string // Response body, typically a JSON error document
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("unexpected HTTP %d: %s", e.StatusCode, e.Body)
+}
+
+// UnauthorizedError is returned when the broker rejects the credentials (HTTP 401 or 403).
+type UnauthorizedError struct {
+	APIError
+}
+
+// NotFoundError is returned when the requested object or endpoint does not exist (HTTP 404).
+type NotFoundError struct {
+	APIError
+}
+
+// RateLimitedError is returned when the broker, or a proxy in front of it, throttles requests (HTTP 429).
+type RateLimitedError struct {
+	APIError
+	RetryAfter time.Duration // Delay requested by the Retry-After header; zero if absent
+}
+
+// ServerError is returned when the broker fails to handle the request (HTTP 5xx),
+// typically while it is starting up or restarting.
+type ServerError struct {
+	APIError
+}
+
+// newAPIError returns the typed error matching the status code of resp.
+func newAPIError(resp *http.Response, path string, body []byte) error {
+	base := APIError{StatusCode: resp.StatusCode, Path: path, Body: string(body)}
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized, resp.StatusCode == http.StatusForbidden:
+		return &UnauthorizedError{base}
+	case resp.StatusCode == http.StatusNotFound:
+		return &NotFoundError{base}
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return &RateLimitedError{APIError: base, RetryAfter: retryAfter(resp.Header.Get("Retry-After"), time.Now())}
+	case resp.StatusCode >= http.StatusInternalServerError:
+		return &ServerError{base}
+	}
+	return &base
+}
+
+// retryAfter parses a Retry-After header, given either in seconds or as an HTTP date.
+func retryAfter(header string, now time.Time) time.Duration {
+	if header == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(header); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(header); err == nil && at.After(now) {
+		return at.Sub(now)
+	}
+	return 0
+}
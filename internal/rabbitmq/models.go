@@ -3,7 +3,13 @@
 // via the management API, as well as helper methods for topology filtering.
 package rabbitmq
 
-import "github.com/Patrick-Ivann/AIM-Q/internal/cli"
+import (
+	"cmp"
+	"slices"
+
+	"github.com/Patrick-Ivann/AIM-Q/internal/cli"
+	"github.com/Patrick-Ivann/AIM-Q/internal/redact"
+)
 
 // Exchange describes a RabbitMQ exchange configuration.
 //
@@ -12,12 +18,34 @@ import "github.com/Patrick-Ivann/AIM-Q/internal/cli"
 //
 // Fields correspond to API JSON response fields.
 type Exchange struct {
-	Name       string         `json:"name"`        // Exchange name
-	Type       string         `json:"type"`        // Exchange type (direct, fanout, topic, headers)
-	Vhost      string         `json:"vhost"`       // Virtual host the exchange belongs to
-	Durable    bool           `json:"durable"`     // True if the exchange survives broker restart
-	AutoDelete bool           `json:"auto_delete"` // True if the exchange is auto-deleted when unused
-	Arguments  map[string]any `json:"arguments"`   // Additional arguments or policies
+	Name       string         `json:"name" yaml:"name"`                                   // Exchange name
+	Type       string         `json:"type" yaml:"type"`                                   // Exchange type (direct, fanout, topic, headers)
+	Vhost      string         `json:"vhost" yaml:"vhost"`                                 // Virtual host the exchange belongs to
+	Durable    bool           `json:"durable" yaml:"durable"`                             // True if the exchange survives broker restart
+	AutoDelete bool           `json:"auto_delete" yaml:"auto_delete"`                     // True if the exchange is auto-deleted when unused
+	Arguments  map[string]any `json:"arguments" yaml:"arguments"`                         // Additional arguments or policies
+	Cluster    string         `json:"cluster,omitempty" yaml:"cluster,omitempty" api:"-"` // Cluster name, set when merging clusters
+
+	// Policies applying to the exchange, set by Topology.ApplyPolicies.
+	Policy              string         `json:"policy,omitempty" yaml:"policy,omitempty" api:"-"`
+	EffectiveDefinition map[string]any `json:"effective_policy_definition,omitempty" yaml:"effective_policy_definition,omitempty" api:"-"`
+}
+
+// ExchangeIcon returns an emoji prefix based on exchange type for visual clarity.
+// Diagrams and the TUI share it, so exchanges look the same in both.
+func ExchangeIcon(t string) string {
+	switch t {
+	case "direct":
+		return "➡️"
+	case "fanout":
+		return "🔄"
+	case "topic":
+		return "🧩"
+	case "headers":
+		return "📋"
+	default:
+		return "❓"
+	}
 }
 
 // Queue describes a RabbitMQ queue configuration.
@@ -26,17 +54,24 @@ type Exchange struct {
 //
 // MessageStats contains runtime counters reflecting the queue state.
 type Queue struct {
-	Name       string         `json:"name"`        // Queue name
-	Vhost      string         `json:"vhost"`       // Virtual host the queue belongs to
-	Durable    bool           `json:"durable"`     // True if the queue survives broker restart
-	AutoDelete bool           `json:"auto_delete"` // True if the queue is auto-deleted when unused
-	Arguments  map[string]any `json:"arguments"`   // Additional arguments or policies
+	Name       string         `json:"name" yaml:"name"`                                   // Queue name
+	Vhost      string         `json:"vhost" yaml:"vhost"`                                 // Virtual host the queue belongs to
+	Durable    bool           `json:"durable" yaml:"durable"`                             // True if the queue survives broker restart
+	AutoDelete bool           `json:"auto_delete" yaml:"auto_delete"`                     // True if the queue is auto-deleted when unused
+	Exclusive  bool           `json:"exclusive" yaml:"exclusive"`                         // True if the queue is used by its declaring connection only
+	Arguments  map[string]any `json:"arguments" yaml:"arguments"`                         // Additional arguments or policies
+	Cluster    string         `json:"cluster,omitempty" yaml:"cluster,omitempty" api:"-"` // Cluster name, set when merging clusters
+
+	// Policies applying to the queue, set by Topology.ApplyPolicies.
+	Policy              string         `json:"policy,omitempty" yaml:"policy,omitempty" api:"-"`
+	OperatorPolicy      string         `json:"operator_policy,omitempty" yaml:"operator_policy,omitempty" api:"-"`
+	EffectiveDefinition map[string]any `json:"effective_policy_definition,omitempty" yaml:"effective_policy_definition,omitempty" api:"-"`
 
 	MessageStats struct {
-		Messages        int `json:"messages"`                // Total messages in the queue
-		MessagesReady   int `json:"messages_ready"`          // Messages ready for delivery to consumers
-		MessagesUnacked int `json:"messages_unacknowledged"` // Messages delivered but unacknowledged
-	} `json:"message_stats"`
+		Messages        int `json:"messages" yaml:"messages"`                               // Total messages in the queue
+		MessagesReady   int `json:"messages_ready" yaml:"messages_ready"`                   // Messages ready for delivery to consumers
+		MessagesUnacked int `json:"messages_unacknowledged" yaml:"messages_unacknowledged"` // Messages delivered but unacknowledged
+	} `json:"message_stats" yaml:"message_stats"`
 }
 
 // Binding represents a relationship connecting an exchange to a queue or another exchange.
@@ -44,42 +79,114 @@ type Queue struct {
 // The Binding routes messages sent to the source exchange to the destination target,
 // optionally filtered by a routing key.
 type Binding struct {
-	Source      string `json:"source"`           // Name of the source exchange
-	Destination string `json:"destination"`      // Name of the destination (queue or exchange)
-	DestType    string `json:"destination_type"` // "queue" or "exchange"
-	Vhost       string `json:"vhost"`            // Virtual host where the binding lives
-	RoutingKey  string `json:"routing_key"`      // Key used to filter/routing messages
+	Source      string         `json:"source" yaml:"source"`                       // Name of the source exchange
+	Destination string         `json:"destination" yaml:"destination"`             // Name of the destination (queue or exchange)
+	DestType    string         `json:"destination_type" yaml:"destination_type"`   // "queue" or "exchange"
+	Vhost       string         `json:"vhost" yaml:"vhost"`                         // Virtual host where the binding lives
+	RoutingKey  string         `json:"routing_key" yaml:"routing_key"`             // Key used to filter/routing messages
+	Arguments   map[string]any `json:"arguments" yaml:"arguments"`                 // Binding arguments, such as the headers matched by headers exchanges
+	Cluster     string         `json:"cluster,omitempty" yaml:"cluster,omitempty"` // Cluster name, set when merging clusters
 }
 
 // Consumer represents a consumer subscribed to a queue.
 //
 // Contains consumer tag and the PID of the channel consuming from the queue.
 type Consumer struct {
-	Queue         string `json:"queue"`        // Queue name the consumer listens on
-	ConsumerTag   string `json:"consumer_tag"` // Consumer tag identifier
-	Vhost         string `json:"vhost"`        // Virtual host of the consumer
+	Queue         string `json:"queue" yaml:"queue"`                         // Queue name the consumer listens on
+	ConsumerTag   string `json:"consumer_tag" yaml:"consumer_tag"`           // Consumer tag identifier
+	Vhost         string `json:"vhost" yaml:"vhost"`                         // Virtual host of the consumer
+	Cluster       string `json:"cluster,omitempty" yaml:"cluster,omitempty"` // Cluster name, set when merging clusters
 	ChannelDetail struct {
-		PID int `json:"pid"` // Process ID of the AMQP channel consuming messages
-	} `json:"channel_details"`
+		PID int `json:"pid" yaml:"pid"` // Process ID of the AMQP channel consuming messages
+	} `json:"channel_details" yaml:"channel_details"`
+}
+
+// Policy describes a RabbitMQ policy applying a definition (DLX, TTL, max-length, ...)
+// to the queues and/or exchanges whose name matches a pattern.
+//
+// When several policies match an object, the one with the highest priority applies.
+// Operator policies have the same shape and cap the values set by policies.
+type Policy struct {
+	Name       string         `json:"name" yaml:"name"`                           // Policy name
+	Vhost      string         `json:"vhost" yaml:"vhost"`                         // Virtual host the policy belongs to
+	Pattern    string         `json:"pattern" yaml:"pattern"`                     // Regular expression matched against object names
+	ApplyTo    string         `json:"apply-to" yaml:"apply-to"`                   // "queues", "exchanges", "all", ...
+	Definition map[string]any `json:"definition" yaml:"definition"`               // Keys applied to matching objects
+	Priority   int            `json:"priority" yaml:"priority"`                   // Higher priority wins on conflicts
+	Cluster    string         `json:"cluster,omitempty" yaml:"cluster,omitempty"` // Cluster name, set when merging clusters
+}
+
+// Shovel describes a shovel moving messages from a source queue or exchange to a
+// destination, usually on another broker, as reported by the shovel management plugin.
+//
+// URIs use the AMQP URI syntax; an URI without host, such as "amqp://", designates
+// the broker running the shovel.
+type Shovel struct {
+	Name            string `json:"name" yaml:"name"`                           // Shovel name
+	Vhost           string `json:"vhost" yaml:"vhost"`                         // Virtual host the shovel is defined in
+	Type            string `json:"type" yaml:"type"`                           // "dynamic" or "static"
+	State           string `json:"state" yaml:"state"`                         // "running", "starting", "terminated", ...
+	SrcURI          string `json:"src_uri" yaml:"src_uri"`                     // Broker messages are consumed from
+	SrcQueue        string `json:"src_queue" yaml:"src_queue"`                 // Source queue, if consuming from a queue
+	SrcExchange     string `json:"src_exchange" yaml:"src_exchange"`           // Source exchange, if consuming from an exchange
+	SrcExchangeKey  string `json:"src_exchange_key" yaml:"src_exchange_key"`   // Routing key bound to the source exchange
+	DestURI         string `json:"dest_uri" yaml:"dest_uri"`                   // Broker messages are published to
+	DestQueue       string `json:"dest_queue" yaml:"dest_queue"`               // Destination queue, if publishing to a queue
+	DestExchange    string `json:"dest_exchange" yaml:"dest_exchange"`         // Destination exchange, if publishing to an exchange
+	DestExchangeKey string `json:"dest_exchange_key" yaml:"dest_exchange_key"` // Routing key used when publishing
+	Cluster         string `json:"cluster,omitempty" yaml:"cluster,omitempty"` // Cluster name, set when merging clusters
+}
+
+// FederationLink describes a link of the federation plugin, through which a local
+// exchange or queue receives messages from its counterpart on an upstream broker.
+type FederationLink struct {
+	Upstream         string `json:"upstream" yaml:"upstream"`                   // Name of the upstream
+	Vhost            string `json:"vhost" yaml:"vhost"`                         // Virtual host of the federated object
+	Type             string `json:"type" yaml:"type"`                           // "exchange" or "queue"
+	Exchange         string `json:"exchange" yaml:"exchange"`                   // Local federated exchange, for exchange links
+	UpstreamExchange string `json:"upstream_exchange" yaml:"upstream_exchange"` // Exchange on the upstream broker
+	Queue            string `json:"queue" yaml:"queue"`                         // Local federated queue, for queue links
+	UpstreamQueue    string `json:"upstream_queue" yaml:"upstream_queue"`       // Queue on the upstream broker
+	URI              string `json:"uri" yaml:"uri"`                             // URI of the upstream broker
+	Status           string `json:"status" yaml:"status"`                       // "running", "starting", "error", ...
+	Cluster          string `json:"cluster,omitempty" yaml:"cluster,omitempty"` // Cluster name, set when merging clusters
+}
+
+// Overview holds broker-wide information returned by the management API's overview endpoint.
+type Overview struct {
+	ClusterName       string `json:"cluster_name" yaml:"cluster_name"`             // Name of the RabbitMQ cluster
+	RabbitMQVersion   string `json:"rabbitmq_version" yaml:"rabbitmq_version"`     // Version of the broker
+	ManagementVersion string `json:"management_version" yaml:"management_version"` // Version of the management plugin
 }
 
 // Topology represents the full snapshot of RabbitMQ server configuration.
 //
-// Aggregates all Exchanges, Queues, Bindings, and Consumers from the management API,
-// usually obtained by Client.FetchTopology.
+// Aggregates all Exchanges, Queues, Bindings, Consumers, Policies, OperatorPolicies,
+// Shovels and FederationLinks from the management API, usually obtained by Client.FetchTopology
+// or LoadDefinitions.
 type Topology struct {
-	Exchanges []Exchange
-	Queues    []Queue
-	Bindings  []Binding
-	Consumers []Consumer
+	Exchanges        []Exchange       `json:"exchanges" yaml:"exchanges"`
+	Queues           []Queue          `json:"queues" yaml:"queues"`
+	Bindings         []Binding        `json:"bindings" yaml:"bindings"`
+	Consumers        []Consumer       `json:"consumers" yaml:"consumers"`
+	Policies         []Policy         `json:"policies" yaml:"policies"`
+	OperatorPolicies []Policy         `json:"operator_policies" yaml:"operator_policies"`
+	Shovels          []Shovel         `json:"shovels" yaml:"shovels"`
+	FederationLinks  []FederationLink `json:"federation_links" yaml:"federation_links"`
+
+	// Host names of the brokers of each merged cluster, by cluster name, set by SetCluster.
+	ClusterHosts map[string][]string `json:"cluster_hosts,omitempty" yaml:"cluster_hosts,omitempty"`
+
+	unfiltered *Topology // Topology Filter was applied to, nil if not filtered
 }
 
 // Filter applies CLI options filtering to the topology.
 //
 // Currently filters by virtual host and exchange name.
-// Returns a new Topology pointer containing only matching resources.
+// Returns a new Topology pointer containing only matching resources, which
+// remembers the topology it was filtered from (see Unfiltered).
 func (t *Topology) Filter(opts cli.Options) *Topology {
-	filtered := &Topology{}
+	filtered := &Topology{ClusterHosts: t.ClusterHosts, unfiltered: t.Unfiltered()}
 
 	for _, ex := range t.Exchanges {
 		if opts.FilterVhost != "" && ex.Vhost != opts.FilterVhost {
@@ -112,5 +219,113 @@ func (t *Topology) Filter(opts cli.Options) *Topology {
 		filtered.Consumers = append(filtered.Consumers, c)
 	}
 
+	for _, p := range t.Policies {
+		if opts.FilterVhost != "" && p.Vhost != opts.FilterVhost {
+			continue
+		}
+		filtered.Policies = append(filtered.Policies, p)
+	}
+
+	for _, p := range t.OperatorPolicies {
+		if opts.FilterVhost != "" && p.Vhost != opts.FilterVhost {
+			continue
+		}
+		filtered.OperatorPolicies = append(filtered.OperatorPolicies, p)
+	}
+
+	for _, s := range t.Shovels {
+		if opts.FilterVhost != "" && s.Vhost != opts.FilterVhost {
+			continue
+		}
+		filtered.Shovels = append(filtered.Shovels, s)
+	}
+
+	for _, l := range t.FederationLinks {
+		if opts.FilterVhost != "" && l.Vhost != opts.FilterVhost {
+			continue
+		}
+		filtered.FederationLinks = append(filtered.FederationLinks, l)
+	}
+
 	return filtered
 }
+
+// Unfiltered returns the topology Filter was first applied to, or t itself if it
+// is not the result of Filter.
+//
+// Renderers use it to follow routes and references through objects the filters
+// hide, such as exchanges excluded by --filter-exchange.
+func (t *Topology) Unfiltered() *Topology {
+	if t.unfiltered != nil {
+		return t.unfiltered
+	}
+	return t
+}
+
+// Redacted returns a copy of the topology where the passwords of the broker URIs
+// of shovels and federation links are masked, to be written to files.
+//
+// The copy shares every other list with t.
+func (t *Topology) Redacted() *Topology {
+	c := *t
+	c.Shovels = slices.Clone(t.Shovels)
+	for i := range c.Shovels {
+		c.Shovels[i].SrcURI = redact.URI(c.Shovels[i].SrcURI)
+		c.Shovels[i].DestURI = redact.URI(c.Shovels[i].DestURI)
+	}
+	c.FederationLinks = slices.Clone(t.FederationLinks)
+	for i := range c.FederationLinks {
+		c.FederationLinks[i].URI = redact.URI(c.FederationLinks[i].URI)
+	}
+	return &c
+}
+
+// Sort orders every resource list deterministically, in place.
+//
+// Objects are sorted by cluster first, then exchanges and queues by vhost then
+// name, bindings by vhost, source, destination type, destination and routing key,
+// consumers by vhost, queue and consumer tag, policies and shovels by vhost then
+// name, and federation links by vhost, local object and upstream.
+// Sorting makes renderer and export output stable across runs.
+func (t *Topology) Sort() {
+	slices.SortStableFunc(t.Exchanges, func(a, b Exchange) int {
+		return cmp.Or(cmp.Compare(a.Cluster, b.Cluster), cmp.Compare(a.Vhost, b.Vhost), cmp.Compare(a.Name, b.Name))
+	})
+	slices.SortStableFunc(t.Queues, func(a, b Queue) int {
+		return cmp.Or(cmp.Compare(a.Cluster, b.Cluster), cmp.Compare(a.Vhost, b.Vhost), cmp.Compare(a.Name, b.Name))
+	})
+	slices.SortStableFunc(t.Bindings, func(a, b Binding) int {
+		return cmp.Or(
+			cmp.Compare(a.Cluster, b.Cluster),
+			cmp.Compare(a.Vhost, b.Vhost),
+			cmp.Compare(a.Source, b.Source),
+			cmp.Compare(a.DestType, b.DestType),
+			cmp.Compare(a.Destination, b.Destination),
+			cmp.Compare(a.RoutingKey, b.RoutingKey),
+		)
+	})
+	slices.SortStableFunc(t.Consumers, func(a, b Consumer) int {
+		return cmp.Or(
+			cmp.Compare(a.Cluster, b.Cluster),
+			cmp.Compare(a.Vhost, b.Vhost),
+			cmp.Compare(a.Queue, b.Queue),
+			cmp.Compare(a.ConsumerTag, b.ConsumerTag),
+		)
+	})
+	for _, policies := range [][]Policy{t.Policies, t.OperatorPolicies} {
+		slices.SortStableFunc(policies, func(a, b Policy) int {
+			return cmp.Or(cmp.Compare(a.Cluster, b.Cluster), cmp.Compare(a.Vhost, b.Vhost), cmp.Compare(a.Name, b.Name))
+		})
+	}
+	slices.SortStableFunc(t.Shovels, func(a, b Shovel) int {
+		return cmp.Or(cmp.Compare(a.Cluster, b.Cluster), cmp.Compare(a.Vhost, b.Vhost), cmp.Compare(a.Name, b.Name))
+	})
+	slices.SortStableFunc(t.FederationLinks, func(a, b FederationLink) int {
+		return cmp.Or(
+			cmp.Compare(a.Cluster, b.Cluster),
+			cmp.Compare(a.Vhost, b.Vhost),
+			cmp.Compare(a.Exchange+a.Queue, b.Exchange+b.Queue),
+			cmp.Compare(a.Upstream, b.Upstream),
+		)
+	})
+}
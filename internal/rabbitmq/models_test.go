@@ -77,6 +77,15 @@ func TestTopology_Filter(t *testing.T) {
 	}
 }
 
+func TestTopology_Unfiltered(t *testing.T) {
+	topology := &rabbitmq.Topology{Exchanges: []rabbitmq.Exchange{{Name: "ex1", Vhost: "vh1"}, {Name: "ex2", Vhost: "vh1"}}}
+
+	assert.Same(t, topology, topology.Unfiltered())
+	filtered := topology.Filter(cli.Options{FilterExchange: "ex1"})
+	assert.Same(t, topology, filtered.Unfiltered())
+	assert.Same(t, topology, filtered.Filter(cli.Options{FilterVhost: "vh1"}).Unfiltered(), "filters do not chain")
+}
+
 func TestExchangeFields(t *testing.T) {
 	ex := rabbitmq.Exchange{
 		Name:       "exname",
@@ -139,3 +148,39 @@ func TestConsumerFields(t *testing.T) {
 	assert.Equal(t, "vh1", c.Vhost)
 	assert.Equal(t, 123, c.ChannelDetail.PID)
 }
+
+func TestTopology_Sort(t *testing.T) {
+	topo := &rabbitmq.Topology{
+		Exchanges: []rabbitmq.Exchange{
+			{Name: "b", Vhost: "vh2"},
+			{Name: "z", Vhost: "vh1"},
+			{Name: "a", Vhost: "vh2"},
+		},
+		Queues: []rabbitmq.Queue{
+			{Name: "q2", Vhost: "vh1"},
+			{Name: "q1", Vhost: "vh1"},
+		},
+		Bindings: []rabbitmq.Binding{
+			{Source: "ex1", Destination: "q1", DestType: "queue", Vhost: "vh1", RoutingKey: "b"},
+			{Source: "ex1", Destination: "ex2", DestType: "exchange", Vhost: "vh1"},
+			{Source: "ex1", Destination: "q1", DestType: "queue", Vhost: "vh1", RoutingKey: "a"},
+		},
+		Consumers: []rabbitmq.Consumer{
+			{Queue: "q1", Vhost: "vh1", ConsumerTag: "c2"},
+			{Queue: "q1", Vhost: "vh1", ConsumerTag: "c1"},
+		},
+	}
+
+	topo.Sort()
+
+	assert.Equal(t, []string{"vh1/z", "vh2/a", "vh2/b"}, []string{
+		topo.Exchanges[0].Vhost + "/" + topo.Exchanges[0].Name,
+		topo.Exchanges[1].Vhost + "/" + topo.Exchanges[1].Name,
+		topo.Exchanges[2].Vhost + "/" + topo.Exchanges[2].Name,
+	})
+	assert.Equal(t, "q1", topo.Queues[0].Name)
+	assert.Equal(t, "exchange", topo.Bindings[0].DestType)
+	assert.Equal(t, "a", topo.Bindings[1].RoutingKey)
+	assert.Equal(t, "b", topo.Bindings[2].RoutingKey)
+	assert.Equal(t, "c1", topo.Consumers[0].ConsumerTag)
+}
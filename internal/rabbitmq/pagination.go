@@ -0,0 +1,90 @@
+package rabbitmq
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// DefaultPageSize is the number of objects requested per page from paginated
+// endpoints when Client.PageSize is not set. It is the maximum the management API accepts.
+const DefaultPageSize = 500
+
+// page is the envelope of a paginated management API response.
+type page[T any] struct {
+	Items     []T `json:"items"`
+	Page      int `json:"page"`
+	PageCount int `json:"page_count"`
+}
+
+// getPaged fetches every page of a paginated endpoint (exchanges, queues) and
+// appends the items to out, decoding one page at a time.
+//
+// Only the columns mapped by T's JSON tags are requested, which keeps responses
+// small on brokers with tens of thousands of objects. A plain JSON array response,
+// as returned by proxies or brokers ignoring pagination, is accepted as the only page.
+func getPaged[T any](ctx context.Context, c *Client, path string, out *[]T) error {
+	pageSize := c.PageSize
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+	query := url.Values{
+		"page_size": {strconv.Itoa(pageSize)},
+		"columns":   {columns(reflect.TypeFor[T]())},
+	}
+
+	for pageNum := 1; ; pageNum++ {
+		query.Set("page", strconv.Itoa(pageNum))
+
+		var raw json.RawMessage
+		if err := c.GetContext(ctx, path+"?"+query.Encode(), &raw); err != nil {
+			return err
+		}
+
+		if bytes.HasPrefix(bytes.TrimSpace(raw), []byte("[")) {
+			var items []T
+			if err := json.Unmarshal(raw, &items); err != nil {
+				return fmt.Errorf("decoding %s: %w", path, err)
+			}
+			*out = append(*out, items...)
+			return nil
+		}
+
+		var p page[T]
+		if err := json.Unmarshal(raw, &p); err != nil {
+			return fmt.Errorf("decoding %s page %d: %w", path, pageNum, err)
+		}
+		*out = append(*out, p.Items...)
+		if pageNum >= p.PageCount {
+			return nil
+		}
+	}
+}
+
+// columns returns the comma-separated management API column projection for a
+// struct type, derived from its JSON tags. Nested structs are expanded using the
+// API's dotted notation, e.g. "message_stats.messages". Fields tagged api:"-"
+// are computed by AIM-Q rather than returned by the API and are skipped.
+func columns(t reflect.Type) string {
+	var names []string
+	for i := range t.NumField() {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "" || name == "-" || field.Tag.Get("api") == "-" {
+			continue
+		}
+		if field.Type.Kind() == reflect.Struct {
+			for _, nested := range strings.Split(columns(field.Type), ",") {
+				names = append(names, name+"."+nested)
+			}
+			continue
+		}
+		names = append(names, name)
+	}
+	return strings.Join(names, ",")
+}
@@ -0,0 +1,97 @@
+package rabbitmq_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Patrick-Ivann/AIM-Q/internal/rabbitmq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pagedServer serves totalQueues queues page by page and records the queries it received.
+func pagedServer(t *testing.T, totalQueues int) (*httptest.Server, *[]string) {
+	t.Helper()
+	var (
+		mu      sync.Mutex
+		queries []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/queues" {
+			_, _ = io.WriteString(w, "[]")
+			return
+		}
+		mu.Lock()
+		queries = append(queries, r.URL.RawQuery)
+		mu.Unlock()
+
+		pageNum, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		pageSize, _ := strconv.Atoi(r.URL.Query().Get("page_size"))
+		pageCount := (totalQueues + pageSize - 1) / pageSize
+
+		var items []string
+		for i := (pageNum - 1) * pageSize; i < min(pageNum*pageSize, totalQueues); i++ {
+			items = append(items, fmt.Sprintf(`{"name":"q%d","vhost":"/"}`, i))
+		}
+		_, _ = fmt.Fprintf(w, `{"items":[%s],"page":%d,"page_count":%d,"page_size":%d,"total_count":%d}`,
+			strings.Join(items, ","), pageNum, pageCount, pageSize, totalQueues)
+	}))
+	t.Cleanup(server.Close)
+	return server, &queries
+}
+
+func TestClient_FetchTopology_Paginated(t *testing.T) {
+	server, queries := pagedServer(t, 25)
+
+	client, err := rabbitmq.NewClient(server.URL, server.Client())
+	require.NoError(t, err)
+	client.PageSize = 10
+
+	topo, err := client.FetchTopologyContext(context.Background())
+	require.NoError(t, err)
+
+	require.Len(t, topo.Queues, 25)
+	assert.Equal(t, "q0", topo.Queues[0].Name)
+	assert.Equal(t, "q24", topo.Queues[24].Name)
+	assert.Len(t, *queries, 3)
+}
+
+func TestClient_FetchTopology_RequestsColumnProjection(t *testing.T) {
+	server, queries := pagedServer(t, 1)
+
+	client, err := rabbitmq.NewClient(server.URL, server.Client())
+	require.NoError(t, err)
+
+	_, err = client.FetchTopology()
+	require.NoError(t, err)
+
+	require.Len(t, *queries, 1)
+	assert.Contains(t, (*queries)[0], "page_size=500")
+	assert.Contains(t, (*queries)[0],
+		"columns=name%2Cvhost%2Cdurable%2Cauto_delete%2Cexclusive%2Carguments%2Cmessage_stats.messages%2C"+
+			"message_stats.messages_ready%2Cmessage_stats.messages_unacknowledged")
+}
+
+func TestClient_FetchTopology_PageDecodeError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/queues" {
+			_, _ = io.WriteString(w, "[]")
+			return
+		}
+		_, _ = io.WriteString(w, `{"items": "not-a-list"}`)
+	}))
+	defer server.Close()
+
+	client, err := rabbitmq.NewClient(server.URL, server.Client())
+	require.NoError(t, err)
+
+	_, err = client.FetchTopology()
+	assert.ErrorContains(t, err, "queues page 1")
+}
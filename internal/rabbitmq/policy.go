@@ -0,0 +1,192 @@
+package rabbitmq
+
+import (
+	"cmp"
+	"maps"
+	"regexp"
+	"slices"
+)
+
+// ApplyPolicies sets the Policy, OperatorPolicy and EffectiveDefinition of every
+// exchange and queue from the policies and operator policies of the topology.
+//
+// Like RabbitMQ, the policy with the highest priority among those of the object
+// vhost whose pattern matches the object name and whose apply-to covers the
+// object applies. The matching operator policy is merged on top of it, the
+// lower value winning for numeric keys such as max-length or message-ttl.
+func (t *Topology) ApplyPolicies() {
+	policies := compilePolicies(t.Policies)
+	operatorPolicies := compilePolicies(t.OperatorPolicies)
+
+	for i := range t.Exchanges {
+		ex := &t.Exchanges[i]
+		p := policies.match(ex.Cluster, ex.Vhost, ex.Name, func(applyTo string) bool {
+			return applyTo == "all" || applyTo == "exchanges"
+		})
+		ex.Policy, ex.EffectiveDefinition = p.name(), p.definition()
+	}
+
+	for i := range t.Queues {
+		q := &t.Queues[i]
+		appliesTo := func(applyTo string) bool { return queueAppliesTo(applyTo, q.QueueType()) }
+		p := policies.match(q.Cluster, q.Vhost, q.Name, appliesTo)
+		op := operatorPolicies.match(q.Cluster, q.Vhost, q.Name, appliesTo)
+		q.Policy, q.OperatorPolicy = p.name(), op.name()
+		q.EffectiveDefinition = mergeDefinitions(p.definition(), op.definition())
+	}
+}
+
+// Effective returns the effective value of a setting such as "alternate-exchange":
+// the argument the exchange was declared with, which takes precedence over
+// policies, or else the value of the effective policy definition.
+func (ex Exchange) Effective(key string) (any, bool) {
+	return effectiveValue(ex.Arguments, key, ex.EffectiveDefinition, key)
+}
+
+// Effective returns the effective value of a setting such as "dead-letter-exchange"
+// or "message-ttl", combining the "x-" argument the queue was declared with and
+// the effective policy definition: the lower value wins for numeric settings and
+// the argument for the others.
+func (q Queue) Effective(key string) (any, bool) {
+	return effectiveValue(q.Arguments, "x-"+key, q.EffectiveDefinition, key)
+}
+
+// QueueType returns the type of the queue declared by its x-queue-type argument:
+// "classic" (the default), "quorum" or "stream".
+func (q Queue) QueueType() string {
+	if t, ok := q.Arguments["x-queue-type"].(string); ok && t != "" {
+		return t
+	}
+	return "classic"
+}
+
+// compiledPolicy is a policy with its compiled pattern.
+type compiledPolicy struct {
+	*Policy
+	re *regexp.Regexp
+}
+
+// policySet holds policies ordered by decreasing priority, ties broken by name.
+type policySet []compiledPolicy
+
+// compilePolicies compiles the patterns of the policies. Policies with a pattern
+// that is not a valid regular expression never match.
+func compilePolicies(policies []Policy) policySet {
+	var set policySet
+	for i := range policies {
+		re, err := regexp.Compile(policies[i].Pattern)
+		if err != nil {
+			continue
+		}
+		set = append(set, compiledPolicy{Policy: &policies[i], re: re})
+	}
+	slices.SortStableFunc(set, func(a, b compiledPolicy) int {
+		return cmp.Or(cmp.Compare(b.Priority, a.Priority), cmp.Compare(a.Name, b.Name))
+	})
+	return set
+}
+
+// match returns the policy applying to the named object, or nil if there is none.
+func (s policySet) match(cluster, vhost, name string, appliesTo func(applyTo string) bool) *Policy {
+	for _, p := range s {
+		if p.Cluster != cluster || p.Vhost != vhost || !appliesTo(cmp.Or(p.ApplyTo, "all")) {
+			continue
+		}
+		if p.re.MatchString(name) {
+			return p.Policy
+		}
+	}
+	return nil
+}
+
+// name returns the name of the policy, "" for nil.
+func (p *Policy) name() string {
+	if p == nil {
+		return ""
+	}
+	return p.Name
+}
+
+// definition returns the definition of the policy, nil for nil.
+func (p *Policy) definition() map[string]any {
+	if p == nil {
+		return nil
+	}
+	return p.Definition
+}
+
+// queueAppliesTo tells if a policy with the given apply-to applies to queues of queueType.
+func queueAppliesTo(applyTo, queueType string) bool {
+	switch applyTo {
+	case "all", "queues":
+		return true
+	case "classic_queues":
+		return queueType == "classic"
+	case "quorum_queues":
+		return queueType == "quorum"
+	case "streams":
+		return queueType == "stream"
+	default:
+		return false
+	}
+}
+
+// mergeDefinitions returns the definition of a policy capped by the one of an
+// operator policy: the lower value wins for numeric keys, the operator policy
+// for the others. It returns nil when both are empty.
+func mergeDefinitions(policy, operator map[string]any) map[string]any {
+	if len(policy) == 0 && len(operator) == 0 {
+		return nil
+	}
+	merged := maps.Clone(policy)
+	if merged == nil {
+		merged = make(map[string]any, len(operator))
+	}
+	for key, value := range operator {
+		merged[key] = lowerOr(merged[key], value, value)
+	}
+	return merged
+}
+
+// effectiveValue combines the value of an argument and of a policy definition key.
+func effectiveValue(arguments map[string]any, argKey string, definition map[string]any, key string) (any, bool) {
+	arg, hasArg := arguments[argKey]
+	def, hasDef := definition[key]
+	switch {
+	case hasArg && hasDef:
+		return lowerOr(arg, def, arg), true
+	case hasArg:
+		return arg, true
+	default:
+		return def, hasDef
+	}
+}
+
+// lowerOr returns the lower of a and b if both are numbers, or else fallback.
+func lowerOr(a, b, fallback any) any {
+	x, aOK := number(a)
+	y, bOK := number(b)
+	if !aOK || !bOK {
+		return fallback
+	}
+	if x <= y {
+		return a
+	}
+	return b
+}
+
+// number converts the numeric values found in decoded JSON and YAML documents to float64.
+func number(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	default:
+		return 0, false
+	}
+}
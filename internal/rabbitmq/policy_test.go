@@ -0,0 +1,98 @@
+package rabbitmq_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Patrick-Ivann/AIM-Q/internal/rabbitmq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTopology_ApplyPolicies_PriorityAndApplyTo(t *testing.T) {
+	topo := &rabbitmq.Topology{
+		Exchanges: []rabbitmq.Exchange{{Name: "orders", Vhost: "/"}},
+		Queues: []rabbitmq.Queue{
+			{Name: "orders", Vhost: "/"},
+			{Name: "orders.quorum", Vhost: "/", Arguments: map[string]any{"x-queue-type": "quorum"}},
+			{Name: "orders", Vhost: "other"},
+		},
+		Policies: []rabbitmq.Policy{
+			{Name: "all-orders", Vhost: "/", Pattern: "^orders", ApplyTo: "all", Priority: 0,
+				Definition: map[string]any{"alternate-exchange": "unrouted"}},
+			{Name: "quorum-dlx", Vhost: "/", Pattern: "^orders", ApplyTo: "quorum_queues", Priority: 10,
+				Definition: map[string]any{"dead-letter-exchange": "dlx"}},
+			{Name: "broken", Vhost: "/", Pattern: "(", ApplyTo: "all", Priority: 100},
+		},
+	}
+
+	topo.ApplyPolicies()
+
+	assert.Equal(t, "all-orders", topo.Exchanges[0].Policy)
+	assert.Equal(t, "all-orders", topo.Queues[0].Policy)
+	assert.Equal(t, "quorum-dlx", topo.Queues[1].Policy, "the highest priority wins")
+	assert.Equal(t, map[string]any{"dead-letter-exchange": "dlx"}, topo.Queues[1].EffectiveDefinition)
+	assert.Empty(t, topo.Queues[2].Policy, "policies only apply within their vhost")
+	assert.Nil(t, topo.Queues[2].EffectiveDefinition)
+}
+
+func TestTopology_ApplyPolicies_OperatorPolicyCapsNumericValues(t *testing.T) {
+	topo := &rabbitmq.Topology{
+		Queues: []rabbitmq.Queue{{Name: "jobs", Vhost: "/"}},
+		Policies: []rabbitmq.Policy{{Name: "jobs", Vhost: "/", Pattern: ".*", ApplyTo: "queues",
+			Definition: map[string]any{"max-length": float64(100000), "message-ttl": float64(1000), "queue-mode": "lazy"}}},
+		OperatorPolicies: []rabbitmq.Policy{{Name: "limits", Vhost: "/", Pattern: ".*", ApplyTo: "queues",
+			Definition: map[string]any{"max-length": float64(5000), "message-ttl": float64(60000), "queue-mode": "default"}}},
+	}
+
+	topo.ApplyPolicies()
+
+	q := topo.Queues[0]
+	assert.Equal(t, "jobs", q.Policy)
+	assert.Equal(t, "limits", q.OperatorPolicy)
+	assert.Equal(t, map[string]any{
+		"max-length":  float64(5000),
+		"message-ttl": float64(1000),
+		"queue-mode":  "default",
+	}, q.EffectiveDefinition)
+	assert.Equal(t, float64(100000), topo.Policies[0].Definition["max-length"], "policies are not modified")
+}
+
+func TestQueue_Effective(t *testing.T) {
+	q := rabbitmq.Queue{
+		Arguments: map[string]any{"x-dead-letter-exchange": "from-args", "x-message-ttl": float64(500)},
+		EffectiveDefinition: map[string]any{
+			"dead-letter-exchange": "from-policy",
+			"message-ttl":          float64(100),
+			"max-length":           float64(10),
+		},
+	}
+
+	tests := map[string]any{
+		"dead-letter-exchange": "from-args",
+		"message-ttl":          float64(100),
+		"max-length":           float64(10),
+	}
+	for key, want := range tests {
+		got, ok := q.Effective(key)
+		assert.True(t, ok, key)
+		assert.Equal(t, want, got, key)
+	}
+
+	_, ok := q.Effective("overflow")
+	assert.False(t, ok)
+}
+
+func TestParseDefinitions_AppliesPolicies(t *testing.T) {
+	topo, err := rabbitmq.ParseDefinitions(strings.NewReader(`{
+		"queues": [{"name": "orders", "vhost": "/"}],
+		"exchanges": [{"name": "orders", "vhost": "/", "type": "topic"}],
+		"policies": [{"name": "ae", "vhost": "/", "pattern": "orders", "apply-to": "exchanges",
+			"definition": {"alternate-exchange": "unrouted"}}]
+	}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "ae", topo.Exchanges[0].Policy)
+	assert.Empty(t, topo.Queues[0].Policy)
+
+	ae, _ := topo.Exchanges[0].Effective("alternate-exchange")
+	assert.Equal(t, "unrouted", ae)
+}
@@ -0,0 +1,95 @@
+package rabbitmq
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"math/rand/v2"
+	"net/url"
+	"time"
+)
+
+// DefaultRetryPolicy is the retry policy of clients created by NewClient.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxRetries: 3,
+	BaseDelay:  500 * time.Millisecond,
+	MaxDelay:   10 * time.Second,
+}
+
+// RetryPolicy controls how failed requests to the management API are retried.
+//
+// Server errors, rate limiting and connection failures other than untrusted TLS
+// certificates are retried with an
+// exponential backoff: the n-th retry waits a random delay between half and all
+// of BaseDelay*2^n, capped at MaxDelay. A Retry-After header sent with a 429
+// response takes precedence. The zero value disables retries.
+type RetryPolicy struct {
+	MaxRetries int           // Number of retries after the first attempt
+	BaseDelay  time.Duration // Delay before the first retry, before jitter
+	MaxDelay   time.Duration // Upper bound of a single delay; zero means no bound
+}
+
+// backoff returns the delay to wait before the given retry, numbered from zero.
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	delay := p.BaseDelay << min(retry, 30)
+	if p.MaxDelay > 0 && (delay > p.MaxDelay || delay <= 0) {
+		delay = p.MaxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+	half := delay / 2
+	return half + rand.N(delay-half+1)
+}
+
+// delay returns how long to wait before retrying after err.
+func (p RetryPolicy) delay(retry int, err error) time.Duration {
+	var limited *RateLimitedError
+	if errors.As(err, &limited) && limited.RetryAfter > 0 {
+		return limited.RetryAfter
+	}
+	return p.backoff(retry)
+}
+
+// retryable reports whether a request that failed with err may succeed if sent again.
+//
+// Errors caused by ctx being canceled or timing out are never retried, nor are
+// certificate verification failures, which sending again cannot fix.
+func retryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil || untrustedCertificate(err) {
+		return false
+	}
+	var (
+		server  *ServerError
+		limited *RateLimitedError
+		netErr  *url.Error
+	)
+	return errors.As(err, &server) || errors.As(err, &limited) || errors.As(err, &netErr)
+}
+
+// untrustedCertificate reports whether err is the failure to verify the TLS
+// certificate of the server.
+func untrustedCertificate(err error) bool {
+	var (
+		verification *tls.CertificateVerificationError
+		authority    x509.UnknownAuthorityError
+		hostname     x509.HostnameError
+		invalid      x509.CertificateInvalidError
+	)
+	return errors.As(err, &verification) || errors.As(err, &authority) ||
+		errors.As(err, &hostname) || errors.As(err, &invalid)
+}
+
+// sleep waits for d, returning early with the context error if ctx is done first.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,170 @@
+package rabbitmq_test
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Patrick-Ivann/AIM-Q/internal/rabbitmq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// flakyServer fails the first failures requests with the given status, then answers with an empty list.
+func flakyServer(t *testing.T, failures int32, status int, header http.Header) (*rabbitmq.Client, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) <= failures {
+			for k, v := range header {
+				w.Header()[k] = v
+			}
+			w.WriteHeader(status)
+			_, _ = io.WriteString(w, `{"error":"flaky"}`)
+			return
+		}
+		_, _ = io.WriteString(w, "[]")
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := rabbitmq.NewClient(server.URL, server.Client())
+	require.NoError(t, err)
+	client.Retry = rabbitmq.RetryPolicy{MaxRetries: 3, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond}
+	return client, &calls
+}
+
+func TestClient_Get_TypedErrors(t *testing.T) {
+	tests := []struct {
+		status int
+		target any
+	}{
+		{http.StatusUnauthorized, new(*rabbitmq.UnauthorizedError)},
+		{http.StatusForbidden, new(*rabbitmq.UnauthorizedError)},
+		{http.StatusNotFound, new(*rabbitmq.NotFoundError)},
+		{http.StatusTooManyRequests, new(*rabbitmq.RateLimitedError)},
+		{http.StatusServiceUnavailable, new(*rabbitmq.ServerError)},
+		{http.StatusTeapot, new(*rabbitmq.APIError)},
+	}
+
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.status), func(t *testing.T) {
+			client, _ := flakyServer(t, 100, tt.status, nil)
+			client.Retry = rabbitmq.RetryPolicy{}
+
+			var out []any
+			err := client.Get("queues", &out)
+			require.Error(t, err)
+			assert.ErrorAs(t, err, tt.target)
+			assert.Contains(t, err.Error(), "unexpected HTTP")
+		})
+	}
+}
+
+func TestClient_Get_RetriesServerErrors(t *testing.T) {
+	client, calls := flakyServer(t, 2, http.StatusServiceUnavailable, nil)
+
+	var out []any
+	require.NoError(t, client.Get("queues", &out))
+	assert.Equal(t, int32(3), calls.Load())
+}
+
+func TestClient_Get_GivesUpAfterMaxRetries(t *testing.T) {
+	client, calls := flakyServer(t, 100, http.StatusBadGateway, nil)
+
+	var out []any
+	err := client.Get("queues", &out)
+
+	var serverErr *rabbitmq.ServerError
+	require.ErrorAs(t, err, &serverErr)
+	assert.Equal(t, http.StatusBadGateway, serverErr.StatusCode)
+	assert.Equal(t, "queues", serverErr.Path)
+	assert.Equal(t, int32(4), calls.Load())
+}
+
+func TestClient_Get_DoesNotRetryClientErrors(t *testing.T) {
+	client, calls := flakyServer(t, 100, http.StatusNotFound, nil)
+
+	var out []any
+	err := client.Get("shovels", &out)
+	assert.ErrorAs(t, err, new(*rabbitmq.NotFoundError))
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestClient_Get_HonorsRetryAfter(t *testing.T) {
+	client, calls := flakyServer(t, 1, http.StatusTooManyRequests, http.Header{"Retry-After": {"1"}})
+
+	start := time.Now()
+	var out []any
+	require.NoError(t, client.Get("queues", &out))
+	assert.GreaterOrEqual(t, time.Since(start), time.Second)
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestClient_Get_RetriesConnectionErrors(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			// Drop the connection without a response, as a restarting broker would.
+			if conn, _, err := w.(http.Hijacker).Hijack(); assert.NoError(t, err) {
+				_ = conn.Close()
+			}
+			return
+		}
+		_, _ = io.WriteString(w, "[]")
+	}))
+	defer server.Close()
+
+	client, err := rabbitmq.NewClient(server.URL, server.Client())
+	require.NoError(t, err)
+	client.Retry.BaseDelay = time.Millisecond
+
+	var out []any
+	require.NoError(t, client.Get("queues", &out))
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestClient_Get_DoesNotRetryUntrustedCertificates(t *testing.T) {
+	var connections atomic.Int32
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "[]")
+	}))
+	server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			connections.Add(1)
+		}
+	}
+	server.Config.ErrorLog = log.New(io.Discard, "", 0)
+	server.StartTLS()
+	defer server.Close()
+
+	// The default client does not trust the certificate of the test server.
+	client, err := rabbitmq.NewClient(server.URL, &http.Client{})
+	require.NoError(t, err)
+	client.Retry.BaseDelay = time.Millisecond
+
+	var out []any
+	err = client.Get("queues", &out)
+	assert.ErrorAs(t, err, new(*tls.CertificateVerificationError))
+	assert.Equal(t, int32(1), connections.Load())
+}
+
+func TestClient_Get_StopsRetryingWhenCanceled(t *testing.T) {
+	client, _ := flakyServer(t, 100, http.StatusServiceUnavailable, nil)
+	client.Retry = rabbitmq.RetryPolicy{MaxRetries: 10, BaseDelay: time.Hour}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	var out []any
+	err := client.GetContext(ctx, "queues", &out)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.True(t, errors.As(err, new(*rabbitmq.ServerError)), "the cause should be kept: %v", err)
+}
@@ -0,0 +1,88 @@
+package rabbitmq
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+)
+
+// TLSOptions configures how HTTPS management endpoints are verified and how
+// the client authenticates to them.
+type TLSOptions struct {
+	CACertFile         string // PEM bundle of CAs trusted in addition to the system pool
+	ClientCertFile     string // PEM client certificate for mutual TLS; requires ClientKeyFile
+	ClientKeyFile      string // PEM private key of ClientCertFile
+	InsecureSkipVerify bool   // Disables server certificate verification; for testing only
+}
+
+// IsZero reports whether no TLS option is set.
+func (o TLSOptions) IsZero() bool {
+	return o == TLSOptions{}
+}
+
+// NewHTTPClient returns an HTTP client for NewClient applying the TLS options.
+//
+// It returns http.DefaultClient when no option is set, and otherwise a client
+// whose transport is a copy of http.DefaultTransport with the TLS configuration
+// replaced, so proxies and timeouts keep their defaults.
+func NewHTTPClient(opts TLSOptions) (*http.Client, error) {
+	if opts.IsZero() {
+		return http.DefaultClient, nil
+	}
+
+	config, err := opts.config()
+	if err != nil {
+		return nil, err
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = config
+	return &http.Client{Transport: transport}, nil
+}
+
+// config builds the TLS configuration, loading the certificate files.
+func (o TLSOptions) config() (*tls.Config, error) {
+	config := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: o.InsecureSkipVerify, //nolint:gosec // explicitly requested by the user
+	}
+
+	if o.CACertFile != "" {
+		pool, err := loadCertPool(o.CACertFile)
+		if err != nil {
+			return nil, err
+		}
+		config.RootCAs = pool
+	}
+
+	if (o.ClientCertFile == "") != (o.ClientKeyFile == "") {
+		return nil, errors.New("client certificate and key must be given together")
+	}
+	if o.ClientCertFile != "" {
+		cert, err := tls.LoadX509KeyPair(o.ClientCertFile, o.ClientKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("loading client certificate: %w", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+	return config, nil
+}
+
+// loadCertPool returns the system certificate pool extended with the CAs of a PEM file.
+func loadCertPool(path string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading CA certificate: %w", err)
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no PEM certificate found in %s", path)
+	}
+	return pool, nil
+}
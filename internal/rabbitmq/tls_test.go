@@ -0,0 +1,141 @@
+package rabbitmq_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Patrick-Ivann/AIM-Q/internal/rabbitmq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writePEM writes a single PEM block to a file in dir and returns its path.
+func writePEM(t *testing.T, dir, name, blockType string, der []byte) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0600))
+	return path
+}
+
+// newTLSServer starts an HTTPS server answering every request with an empty list
+// and returns it with the path of its certificate in PEM form.
+//
+// If clientCAs is not nil, the server requires a client certificate signed by one of them.
+func newTLSServer(t *testing.T, clientCAs *x509.CertPool) (*httptest.Server, string) {
+	t.Helper()
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "[]")
+	}))
+	server.TLS = &tls.Config{MinVersion: tls.VersionTLS12}
+	if clientCAs != nil {
+		server.TLS.ClientAuth = tls.RequireAndVerifyClientCert
+		server.TLS.ClientCAs = clientCAs
+	}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+	return server, writePEM(t, t.TempDir(), "ca.pem", "CERTIFICATE", server.Certificate().Raw)
+}
+
+// newClientCertificate creates a self-signed client certificate and returns the
+// paths of the certificate and key files and a pool trusting it.
+func newClientCertificate(t *testing.T) (certFile, keyFile string, pool *x509.CertPool) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "aimq"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	pool = x509.NewCertPool()
+	pool.AddCert(cert)
+
+	dir := t.TempDir()
+	return writePEM(t, dir, "client.pem", "CERTIFICATE", der), writePEM(t, dir, "client.key", "EC PRIVATE KEY", keyDER), pool
+}
+
+// get performs a request to the server through a client built from the TLS options.
+func get(t *testing.T, server *httptest.Server, opts rabbitmq.TLSOptions) error {
+	t.Helper()
+	httpClient, err := rabbitmq.NewHTTPClient(opts)
+	require.NoError(t, err)
+
+	client, err := rabbitmq.NewClient(server.URL, httpClient)
+	require.NoError(t, err)
+	client.Retry = rabbitmq.RetryPolicy{}
+
+	var out []any
+	return client.Get("queues", &out)
+}
+
+func TestNewHTTPClient_Default(t *testing.T) {
+	httpClient, err := rabbitmq.NewHTTPClient(rabbitmq.TLSOptions{})
+	require.NoError(t, err)
+	assert.Same(t, http.DefaultClient, httpClient)
+}
+
+func TestNewHTTPClient_PrivateCA(t *testing.T) {
+	server, caFile := newTLSServer(t, nil)
+
+	assert.Error(t, get(t, server, rabbitmq.TLSOptions{}), "an unknown CA must be rejected")
+	assert.NoError(t, get(t, server, rabbitmq.TLSOptions{CACertFile: caFile}))
+}
+
+func TestNewHTTPClient_InsecureSkipVerify(t *testing.T) {
+	server, _ := newTLSServer(t, nil)
+
+	assert.NoError(t, get(t, server, rabbitmq.TLSOptions{InsecureSkipVerify: true}))
+}
+
+func TestNewHTTPClient_ClientCertificate(t *testing.T) {
+	certFile, keyFile, pool := newClientCertificate(t)
+	server, caFile := newTLSServer(t, pool)
+
+	assert.Error(t, get(t, server, rabbitmq.TLSOptions{CACertFile: caFile}), "a client certificate is required")
+	assert.NoError(t, get(t, server, rabbitmq.TLSOptions{
+		CACertFile:     caFile,
+		ClientCertFile: certFile,
+		ClientKeyFile:  keyFile,
+	}))
+}
+
+func TestNewHTTPClient_InvalidOptions(t *testing.T) {
+	notPEM := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(notPEM, []byte("not a certificate"), 0600))
+
+	tests := map[string]rabbitmq.TLSOptions{
+		"missing CA file":  {CACertFile: filepath.Join(t.TempDir(), "missing.pem")},
+		"CA file not PEM":  {CACertFile: notPEM},
+		"cert without key": {ClientCertFile: notPEM},
+		"invalid key pair": {ClientCertFile: notPEM, ClientKeyFile: notPEM},
+	}
+	for name, opts := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := rabbitmq.NewHTTPClient(opts)
+			assert.Error(t, err)
+		})
+	}
+}
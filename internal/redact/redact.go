@@ -0,0 +1,35 @@
+// Package redact scrubs credentials from URIs and free text so that management
+// passwords never end up in generated diagrams, snapshot files or logs.
+package redact
+
+import (
+	"net/url"
+	"regexp"
+)
+
+// Mask replaces redacted secrets.
+const Mask = "xxxxx"
+
+// userinfoPattern matches the "user:password@" part of URLs embedded in text.
+var userinfoPattern = regexp.MustCompile(`([a-zA-Z][a-zA-Z0-9+.-]*://[^/\s:@]*):[^@\s/]*@`)
+
+// URI returns raw with the password of its userinfo replaced by Mask.
+//
+// The user name is kept so that the broker account remains identifiable.
+// Values that cannot be parsed as a URL are scrubbed like free text.
+func URI(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.User == nil {
+		return String(raw)
+	}
+	if _, hasPassword := u.User.Password(); hasPassword {
+		u.User = url.UserPassword(u.User.Username(), Mask)
+	}
+	return u.String()
+}
+
+// String replaces the password of every URL embedded in s by Mask, e.g. in
+// error messages that quote the URI they failed to parse.
+func String(s string) string {
+	return userinfoPattern.ReplaceAllString(s, "${1}:"+Mask+"@")
+}
@@ -0,0 +1,86 @@
+package routing
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Patrick-Ivann/AIM-Q/internal/rabbitmq"
+)
+
+// matchFunc tells if a binding of an exchange matches a message.
+type matchFunc func(b rabbitmq.Binding, msg Message) bool
+
+// matcher returns the binding matching function of an exchange type.
+func matcher(exchangeType string) (matchFunc, bool) {
+	switch exchangeType {
+	case "direct":
+		return func(b rabbitmq.Binding, msg Message) bool { return b.RoutingKey == msg.RoutingKey }, true
+	case "fanout":
+		return func(rabbitmq.Binding, Message) bool { return true }, true
+	case "topic":
+		return func(b rabbitmq.Binding, msg Message) bool { return TopicMatch(b.RoutingKey, msg.RoutingKey) }, true
+	case "headers":
+		return func(b rabbitmq.Binding, msg Message) bool { return HeadersMatch(b.Arguments, msg.Headers) }, true
+	default:
+		return nil, false
+	}
+}
+
+// TopicMatch tells if a routing key matches the binding key of a topic exchange.
+//
+// Keys are lists of words separated by dots; in the binding key, "*" matches
+// exactly one word and "#" zero or more words.
+func TopicMatch(bindingKey, routingKey string) bool {
+	return matchWords(strings.Split(bindingKey, "."), strings.Split(routingKey, "."))
+}
+
+// matchWords matches the words of a routing key against the words of a binding key.
+func matchWords(pattern, words []string) bool {
+	if len(pattern) == 0 {
+		return len(words) == 0
+	}
+	switch pattern[0] {
+	case "#":
+		// Try every number of words for "#", including none.
+		for i := 0; i <= len(words); i++ {
+			if matchWords(pattern[1:], words[i:]) {
+				return true
+			}
+		}
+		return false
+	case "*":
+		return len(words) > 0 && matchWords(pattern[1:], words[1:])
+	default:
+		return len(words) > 0 && pattern[0] == words[0] && matchWords(pattern[1:], words[1:])
+	}
+}
+
+// HeadersMatch tells if message headers match the arguments of a binding of a headers exchange.
+//
+// The x-match argument selects whether all (the default) or any of the other
+// arguments must match a header. Arguments starting with "x-" are ignored unless
+// x-match is "all-with-x" or "any-with-x". An argument without value matches the
+// presence of the header; values are compared by their string representation.
+func HeadersMatch(arguments, headers map[string]any) bool {
+	mode := "all"
+	if m, ok := arguments["x-match"].(string); ok {
+		mode = m
+	}
+	withX := strings.HasSuffix(mode, "-with-x")
+	matchAny := strings.HasPrefix(mode, "any")
+
+	for key, want := range arguments {
+		if key == "x-match" || (!withX && strings.HasPrefix(key, "x-")) {
+			continue
+		}
+		got, ok := headers[key]
+		matched := ok && (want == nil || fmt.Sprint(want) == fmt.Sprint(got))
+		if matched && matchAny {
+			return true
+		}
+		if !matched && !matchAny {
+			return false
+		}
+	}
+	return !matchAny
+}
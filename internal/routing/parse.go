@@ -0,0 +1,44 @@
+package routing
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Patrick-Ivann/AIM-Q/internal/rabbitmq"
+)
+
+// headerPrefix prefixes the header fields of a message specification.
+const headerPrefix = "header."
+
+// ParseMessage parses a message specification made of comma-separated key=value
+// fields: exchange (required, "" or DefaultExchange for the default exchange),
+// key (the routing key), vhost (DefaultVhost if omitted) and header.NAME for
+// each header, e.g. "exchange=orders,key=order.created.eu,header.region=eu".
+func ParseMessage(spec string) (Message, error) {
+	msg := Message{Vhost: rabbitmq.DefaultVhost, Headers: make(map[string]any)}
+	hasExchange := false
+
+	for _, field := range strings.Split(spec, ",") {
+		name, value, ok := strings.Cut(field, "=")
+		if !ok {
+			return msg, fmt.Errorf("field %q is not of the form key=value", field)
+		}
+		switch name = strings.TrimSpace(name); {
+		case name == "exchange":
+			msg.Exchange, hasExchange = value, true
+		case name == "key":
+			msg.RoutingKey = value
+		case name == "vhost":
+			msg.Vhost = value
+		case strings.HasPrefix(name, headerPrefix) && len(name) > len(headerPrefix):
+			msg.Headers[strings.TrimPrefix(name, headerPrefix)] = value
+		default:
+			return msg, fmt.Errorf("unknown field %q (expected exchange, key, vhost or %sNAME)", name, headerPrefix)
+		}
+	}
+
+	if !hasExchange {
+		return msg, fmt.Errorf("missing exchange field")
+	}
+	return msg, nil
+}
@@ -0,0 +1,38 @@
+package routing_test
+
+import (
+	"testing"
+
+	"github.com/Patrick-Ivann/AIM-Q/internal/routing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMessage(t *testing.T) {
+	msg, err := routing.ParseMessage("exchange=orders,key=order.created.eu,vhost=shop,header.region=eu")
+	require.NoError(t, err)
+	assert.Equal(t, routing.Message{
+		Vhost:      "shop",
+		Exchange:   "orders",
+		RoutingKey: "order.created.eu",
+		Headers:    map[string]any{"region": "eu"},
+	}, msg)
+
+	msg, err = routing.ParseMessage("exchange=,key=jobs")
+	require.NoError(t, err)
+	assert.Equal(t, "/", msg.Vhost)
+	assert.Empty(t, msg.Exchange)
+}
+
+func TestParseMessage_Errors(t *testing.T) {
+	tests := map[string]string{
+		"key=order":             "missing exchange field",
+		"exchange=orders,key":   `field "key" is not of the form key=value`,
+		"exchange=orders,to=eu": `unknown field "to"`,
+		"exchange=x,header.=1":  `unknown field "header."`,
+	}
+	for spec, want := range tests {
+		_, err := routing.ParseMessage(spec)
+		assert.ErrorContains(t, err, want, spec)
+	}
+}
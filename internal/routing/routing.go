@@ -0,0 +1,213 @@
+// Package routing simulates how RabbitMQ routes a published message through the
+// exchanges, bindings and alternate exchanges of a topology.
+package routing
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/Patrick-Ivann/AIM-Q/internal/rabbitmq"
+)
+
+// DefaultExchange is the name under which the default exchange, whose real name
+// is empty, can also be addressed.
+const DefaultExchange = "amq.default"
+
+// Ways a message goes from an exchange to the next queue or exchange.
+const (
+	ViaBinding   = "binding"            // A binding matching the message
+	ViaDefault   = "default"            // The implicit binding of the default exchange to every queue
+	ViaAlternate = "alternate-exchange" // The alternate exchange of an exchange no binding matched
+)
+
+// Message describes a published message.
+type Message struct {
+	Vhost      string         // Virtual host of the exchange
+	Exchange   string         // Exchange the message is published to, "" for the default exchange
+	RoutingKey string         // Routing key of the message
+	Headers    map[string]any // Headers, matched by headers exchanges
+}
+
+// Hop is a step of the path of a message, from an exchange to a queue or an exchange.
+type Hop struct {
+	Exchange     string            // Exchange routing the message
+	ExchangeType string            // Type of Exchange
+	Via          string            // ViaBinding, ViaDefault or ViaAlternate
+	Binding      *rabbitmq.Binding // Binding followed, for ViaBinding
+	DestType     string            // "queue" or "exchange"
+	Destination  string            // Name of the queue or exchange the message goes to
+}
+
+// Route is a queue receiving the message and the path the message takes to it.
+type Route struct {
+	Queue string
+	Path  []Hop
+}
+
+// Result is the outcome of a simulation.
+//
+// A queue reached through several paths receives a single copy of the message,
+// like in RabbitMQ, and is reported with the first path found.
+type Result struct {
+	Message  Message
+	Routes   []Route  // Queues receiving the message, in the order they were reached
+	Warnings []string // Parts of the topology the simulation could not follow
+}
+
+// Unroutable tells if no queue receives the message.
+func (r *Result) Unroutable() bool {
+	return len(r.Routes) == 0
+}
+
+// Simulate returns the queues of the topology that receive msg and the paths it takes.
+//
+// Direct, fanout, topic and headers exchanges are supported, as well as
+// exchange-to-exchange bindings and alternate exchanges, read from the effective
+// configuration so that alternate exchanges set by policies are followed. Each
+// exchange routes the message at most once, which breaks binding cycles.
+// It fails if the exchange msg is published to does not exist.
+func Simulate(topology *rabbitmq.Topology, msg Message) (*Result, error) {
+	if msg.Exchange == DefaultExchange {
+		msg.Exchange = ""
+	}
+
+	s := newSimulator(topology, msg)
+	if _, ok := s.exchanges[msg.Exchange]; !ok {
+		return nil, fmt.Errorf("exchange %q not found in vhost %q", msg.Exchange, msg.Vhost)
+	}
+	s.route(msg.Exchange, nil)
+	return s.result, nil
+}
+
+// simulator holds the objects of the message vhost and the state of a simulation.
+type simulator struct {
+	exchanges map[string]rabbitmq.Exchange
+	bindings  map[string][]rabbitmq.Binding // By source exchange
+	queues    map[string]struct{}
+
+	visited   map[string]struct{} // Exchanges that routed the message
+	delivered map[string]struct{} // Queues that received the message
+	result    *Result
+}
+
+// newSimulator indexes the objects of the message vhost.
+func newSimulator(topology *rabbitmq.Topology, msg Message) *simulator {
+	s := &simulator{
+		// The default exchange is not part of definitions exports.
+		exchanges: map[string]rabbitmq.Exchange{"": {Vhost: msg.Vhost, Type: "direct"}},
+		bindings:  make(map[string][]rabbitmq.Binding),
+		queues:    make(map[string]struct{}),
+		visited:   make(map[string]struct{}),
+		delivered: make(map[string]struct{}),
+		result:    &Result{Message: msg},
+	}
+	for _, ex := range topology.Exchanges {
+		if ex.Vhost == msg.Vhost {
+			s.exchanges[ex.Name] = ex
+		}
+	}
+	for _, b := range topology.Bindings {
+		if b.Vhost == msg.Vhost {
+			s.bindings[b.Source] = append(s.bindings[b.Source], b)
+		}
+	}
+	for _, q := range topology.Queues {
+		if q.Vhost == msg.Vhost {
+			s.queues[q.Name] = struct{}{}
+		}
+	}
+	return s
+}
+
+// route routes the message through the named exchange, reached by path.
+func (s *simulator) route(name string, path []Hop) {
+	if _, seen := s.visited[name]; seen {
+		return
+	}
+	s.visited[name] = struct{}{}
+
+	ex, ok := s.exchanges[name]
+	if !ok {
+		s.warn("exchange %q does not exist", name)
+		return
+	}
+
+	if name == "" {
+		s.routeDefault(path)
+		return
+	}
+
+	if !s.routeBindings(ex, path) {
+		s.routeAlternate(ex, path)
+	}
+}
+
+// routeDefault delivers the message to the queue named after its routing key, if any.
+func (s *simulator) routeDefault(path []Hop) {
+	queue := s.result.Message.RoutingKey
+	if _, ok := s.queues[queue]; !ok {
+		return
+	}
+	s.follow(path, Hop{ExchangeType: "direct", Via: ViaDefault, DestType: "queue", Destination: queue})
+}
+
+// routeBindings follows the bindings of the exchange matching the message and
+// reports whether there was any.
+func (s *simulator) routeBindings(ex rabbitmq.Exchange, path []Hop) bool {
+	match, ok := matcher(ex.Type)
+	if !ok {
+		s.warn("exchange %q has unsupported type %q", ex.Name, ex.Type)
+		return false
+	}
+
+	matched := false
+	for _, b := range s.bindings[ex.Name] {
+		if !match(b, s.result.Message) {
+			continue
+		}
+		matched = true
+		s.follow(path, Hop{
+			Exchange:     ex.Name,
+			ExchangeType: ex.Type,
+			Via:          ViaBinding,
+			Binding:      &b,
+			DestType:     b.DestType,
+			Destination:  b.Destination,
+		})
+	}
+	return matched
+}
+
+// routeAlternate routes the message to the alternate exchange of ex, if it has one.
+func (s *simulator) routeAlternate(ex rabbitmq.Exchange, path []Hop) {
+	ae, ok := ex.Effective("alternate-exchange")
+	if !ok {
+		return
+	}
+	s.follow(path, Hop{
+		Exchange:     ex.Name,
+		ExchangeType: ex.Type,
+		Via:          ViaAlternate,
+		DestType:     "exchange",
+		Destination:  fmt.Sprint(ae),
+	})
+}
+
+// follow extends path with hop and delivers the message to its destination.
+func (s *simulator) follow(path []Hop, hop Hop) {
+	path = append(slices.Clip(path), hop)
+	if hop.DestType == "exchange" {
+		s.route(hop.Destination, path)
+		return
+	}
+	if _, done := s.delivered[hop.Destination]; done {
+		return
+	}
+	s.delivered[hop.Destination] = struct{}{}
+	s.result.Routes = append(s.result.Routes, Route{Queue: hop.Destination, Path: path})
+}
+
+// warn records a part of the topology the simulation could not follow.
+func (s *simulator) warn(format string, args ...any) {
+	s.result.Warnings = append(s.result.Warnings, fmt.Sprintf(format, args...))
+}
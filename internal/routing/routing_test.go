@@ -0,0 +1,165 @@
+package routing_test
+
+import (
+	"bytes"
+	"slices"
+	"testing"
+
+	"github.com/Patrick-Ivann/AIM-Q/internal/rabbitmq"
+	"github.com/Patrick-Ivann/AIM-Q/internal/routing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// topology has a topic exchange bound to queues and to a fanout exchange, a
+// headers exchange, and an alternate exchange collecting unroutable events.
+func topology() *rabbitmq.Topology {
+	return &rabbitmq.Topology{
+		Exchanges: []rabbitmq.Exchange{
+			{Name: "events", Vhost: "/", Type: "topic", Arguments: map[string]any{"alternate-exchange": "unrouted"}},
+			{Name: "audit", Vhost: "/", Type: "fanout"},
+			{Name: "unrouted", Vhost: "/", Type: "fanout"},
+			{Name: "reports", Vhost: "/", Type: "headers"},
+		},
+		Queues: []rabbitmq.Queue{
+			{Name: "orders.eu", Vhost: "/"},
+			{Name: "orders.all", Vhost: "/"},
+			{Name: "audit.log", Vhost: "/"},
+			{Name: "lost", Vhost: "/"},
+			{Name: "pdf", Vhost: "/"},
+		},
+		Bindings: []rabbitmq.Binding{
+			{Source: "events", Vhost: "/", DestType: "queue", Destination: "orders.eu", RoutingKey: "order.*.eu"},
+			{Source: "events", Vhost: "/", DestType: "queue", Destination: "orders.all", RoutingKey: "order.#"},
+			{Source: "events", Vhost: "/", DestType: "exchange", Destination: "audit", RoutingKey: "#"},
+			{Source: "audit", Vhost: "/", DestType: "queue", Destination: "audit.log"},
+			{Source: "audit", Vhost: "/", DestType: "exchange", Destination: "events"},
+			{Source: "unrouted", Vhost: "/", DestType: "queue", Destination: "lost"},
+			{Source: "reports", Vhost: "/", DestType: "queue", Destination: "pdf",
+				Arguments: map[string]any{"x-match": "all", "format": "pdf", "type": "report"}},
+		},
+	}
+}
+
+func queues(r *routing.Result) []string {
+	var names []string
+	for _, route := range r.Routes {
+		names = append(names, route.Queue)
+	}
+	return names
+}
+
+func TestSimulate_TopicAndExchangeToExchange(t *testing.T) {
+	result, err := routing.Simulate(topology(), routing.Message{Vhost: "/", Exchange: "events", RoutingKey: "order.created.eu"})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"orders.eu", "orders.all", "audit.log"}, queues(result))
+	assert.Empty(t, result.Warnings, "the audit → events cycle is broken")
+
+	audit := result.Routes[2].Path
+	require.Len(t, audit, 2)
+	assert.Equal(t, "events", audit[0].Exchange)
+	assert.Equal(t, "audit", audit[0].Destination)
+	assert.Equal(t, "audit", audit[1].Exchange)
+	assert.Equal(t, "audit.log", audit[1].Destination)
+}
+
+func TestSimulate_AlternateExchange(t *testing.T) {
+	topo := topology()
+	topo.Bindings = slices.Delete(topo.Bindings, 2, 3) // Without the catch-all binding to audit
+
+	result, err := routing.Simulate(topo, routing.Message{Vhost: "/", Exchange: "events", RoutingKey: "invoice.paid"})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"lost"}, queues(result))
+	assert.Equal(t, routing.ViaAlternate, result.Routes[0].Path[0].Via)
+}
+
+func TestSimulate_DefaultExchange(t *testing.T) {
+	result, err := routing.Simulate(topology(), routing.Message{Vhost: "/", Exchange: routing.DefaultExchange, RoutingKey: "lost"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"lost"}, queues(result))
+	assert.Equal(t, routing.ViaDefault, result.Routes[0].Path[0].Via)
+
+	result, err = routing.Simulate(topology(), routing.Message{Vhost: "/", RoutingKey: "missing"})
+	require.NoError(t, err)
+	assert.True(t, result.Unroutable())
+}
+
+func TestSimulate_Headers(t *testing.T) {
+	msg := routing.Message{Vhost: "/", Exchange: "reports", Headers: map[string]any{"format": "pdf", "type": "report"}}
+	result, err := routing.Simulate(topology(), msg)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"pdf"}, queues(result))
+
+	msg.Headers = map[string]any{"format": "pdf"}
+	result, err = routing.Simulate(topology(), msg)
+	require.NoError(t, err)
+	assert.True(t, result.Unroutable())
+}
+
+func TestSimulate_UnknownExchange(t *testing.T) {
+	_, err := routing.Simulate(topology(), routing.Message{Vhost: "other", Exchange: "events"})
+	assert.ErrorContains(t, err, `exchange "events" not found in vhost "other"`)
+}
+
+func TestSimulate_UnsupportedExchangeType(t *testing.T) {
+	topo := &rabbitmq.Topology{Exchanges: []rabbitmq.Exchange{{Name: "hash", Vhost: "/", Type: "x-consistent-hash"}}}
+
+	result, err := routing.Simulate(topo, routing.Message{Vhost: "/", Exchange: "hash"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{`exchange "hash" has unsupported type "x-consistent-hash"`}, result.Warnings)
+}
+
+func TestTopicMatch(t *testing.T) {
+	tests := []struct {
+		bindingKey, routingKey string
+		want                   bool
+	}{
+		{"order.created", "order.created", true},
+		{"order.*", "order.created", true},
+		{"order.*", "order.created.eu", false},
+		{"order.#", "order", true},
+		{"order.#", "order.created.eu", true},
+		{"#.eu", "order.created.eu", true},
+		{"*.*.eu", "order.eu", false},
+		{"#", "", true},
+		{"order.#.eu", "order.eu", true},
+		{"order.#.eu", "order.created.us", false},
+	}
+
+	for _, tc := range tests {
+		assert.Equal(t, tc.want, routing.TopicMatch(tc.bindingKey, tc.routingKey), "%s ~ %s", tc.bindingKey, tc.routingKey)
+	}
+}
+
+func TestHeadersMatch(t *testing.T) {
+	headers := map[string]any{"format": "pdf", "pages": "3", "x-trace": "on"}
+
+	assert.True(t, routing.HeadersMatch(map[string]any{"format": "pdf", "pages": float64(3)}, headers))
+	assert.False(t, routing.HeadersMatch(map[string]any{"format": "pdf", "lang": "en"}, headers))
+	assert.True(t, routing.HeadersMatch(map[string]any{"x-match": "any", "format": "pdf", "lang": "en"}, headers))
+	assert.False(t, routing.HeadersMatch(map[string]any{"x-match": "any", "lang": "en"}, headers))
+	assert.True(t, routing.HeadersMatch(map[string]any{"format": nil}, headers), "an argument without value matches presence")
+	assert.True(t, routing.HeadersMatch(map[string]any{"x-trace": "off"}, headers), "x- arguments are ignored")
+	assert.False(t, routing.HeadersMatch(map[string]any{"x-match": "all-with-x", "x-trace": "off"}, headers))
+}
+
+func TestResult_WriteText(t *testing.T) {
+	result, err := routing.Simulate(topology(), routing.Message{Vhost: "/", Exchange: "events", RoutingKey: "order.created.us"})
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, result.WriteText(&buf))
+	assert.Equal(t, `2 queue(s) receive the message:
+  orders.all
+    events (topic) --["order.#"]--> queue orders.all
+  audit.log
+    events (topic) --["#"]--> exchange audit
+    audit (fanout) --> queue audit.log
+`, buf.String())
+
+	buf.Reset()
+	require.NoError(t, (&routing.Result{}).WriteText(&buf))
+	assert.Equal(t, "Unroutable: no queue receives the message.\n", buf.String())
+}
@@ -0,0 +1,57 @@
+package routing
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteText writes a human-readable report of the queues receiving the message,
+// each followed by the path taken, one hop per line.
+func (r *Result) WriteText(w io.Writer) error {
+	var sb strings.Builder
+
+	if r.Unroutable() {
+		sb.WriteString("Unroutable: no queue receives the message.\n")
+	} else {
+		sb.WriteString(fmt.Sprintf("%d queue(s) receive the message:\n", len(r.Routes)))
+	}
+	for _, route := range r.Routes {
+		sb.WriteString(fmt.Sprintf("  %s\n", route.Queue))
+		for _, hop := range route.Path {
+			sb.WriteString(fmt.Sprintf("    %s\n", describeHop(hop)))
+		}
+	}
+	for _, warning := range r.Warnings {
+		sb.WriteString(fmt.Sprintf("warning: %s\n", warning))
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// describeHop renders a hop as "exchange (type) --[how]--> kind destination",
+// how being the binding key or arguments matched, or the reason of the hop.
+// Fanout bindings match regardless of the key, which is then omitted.
+func describeHop(hop Hop) string {
+	arrow := "-->"
+	switch {
+	case hop.Via == ViaDefault:
+		arrow = "--[default]-->"
+	case hop.Via == ViaAlternate:
+		arrow = "--[alternate-exchange]-->"
+	case hop.ExchangeType == "headers":
+		arrow = fmt.Sprintf("--[%v]-->", hop.Binding.Arguments)
+	case hop.ExchangeType != "fanout":
+		arrow = fmt.Sprintf("--[%q]-->", hop.Binding.RoutingKey)
+	}
+	return fmt.Sprintf("%s (%s) %s %s %s", exchangeLabel(hop.Exchange), hop.ExchangeType, arrow, hop.DestType, hop.Destination)
+}
+
+// exchangeLabel returns the name of an exchange, DefaultExchange for the default exchange.
+func exchangeLabel(name string) string {
+	if name == "" {
+		return DefaultExchange
+	}
+	return name
+}
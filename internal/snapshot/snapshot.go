@@ -0,0 +1,82 @@
+// Package snapshot saves fetched RabbitMQ topologies to files, together with
+// metadata describing where and when they were captured, and loads them back.
+package snapshot
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/Patrick-Ivann/AIM-Q/internal/rabbitmq"
+)
+
+// FormatVersion identifies the layout of snapshot files written by Save.
+const FormatVersion = 1
+
+// Metadata describes the broker a snapshot was taken from.
+type Metadata struct {
+	BrokerURI       string    `json:"broker_uri"`       // Management API URL, without credentials
+	Timestamp       time.Time `json:"timestamp"`        // Time the topology was fetched
+	ClusterName     string    `json:"cluster_name"`     // Name of the RabbitMQ cluster
+	RabbitMQVersion string    `json:"rabbitmq_version"` // Version of the broker
+}
+
+// Snapshot is a topology captured at a point in time.
+type Snapshot struct {
+	FormatVersion int                `json:"format_version"`
+	Metadata      Metadata           `json:"metadata"`
+	Topology      *rabbitmq.Topology `json:"topology"`
+}
+
+// New returns a snapshot of the topology with the current format version.
+func New(metadata Metadata, topology *rabbitmq.Topology) *Snapshot {
+	return &Snapshot{
+		FormatVersion: FormatVersion,
+		Metadata:      metadata,
+		Topology:      topology,
+	}
+}
+
+// Save writes the snapshot to path as indented JSON, replacing any existing file.
+//
+// The passwords of the broker URIs of shovels and federation links are masked
+// (see rabbitmq.Topology.Redacted), as the broker URI of the metadata is.
+func Save(path string, s *Snapshot) error {
+	redacted := *s
+	if s.Topology != nil {
+		redacted.Topology = s.Topology.Redacted()
+	}
+	data, err := json.MarshalIndent(redacted, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding snapshot: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("writing snapshot: %w", err)
+	}
+	return nil
+}
+
+// Load reads a snapshot written by Save.
+//
+// Returns an error if the file cannot be decoded, was written by a newer
+// version of AIM-Q or holds no topology.
+func Load(path string) (*Snapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading snapshot: %w", err)
+	}
+
+	var s Snapshot
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("decoding snapshot %s: %w", path, err)
+	}
+	if s.FormatVersion > FormatVersion {
+		return nil, fmt.Errorf("snapshot %s has format version %d, this build supports up to %d",
+			path, s.FormatVersion, FormatVersion)
+	}
+	if s.Topology == nil {
+		return nil, fmt.Errorf("snapshot %s holds no topology", path)
+	}
+	return &s, nil
+}
@@ -0,0 +1,187 @@
+// Package tui provides an interactive terminal browser for a RabbitMQ topology.
+package tui
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Patrick-Ivann/AIM-Q/internal/cli"
+	"github.com/Patrick-Ivann/AIM-Q/internal/lint"
+	"github.com/Patrick-Ivann/AIM-Q/internal/rabbitmq"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// historySize is the number of samples kept per queue for sparklines.
+const historySize = 30
+
+// Fetcher loads a fresh topology, typically from the management API.
+//
+// The context is canceled when the user quits, aborting any in-flight refresh.
+type Fetcher func(ctx context.Context) (*rabbitmq.Topology, error)
+
+// App is the interactive topology browser: a navigable tree, a detail pane
+// describing the selected object and a live queue table with sparklines.
+type App struct {
+	app     *tview.Application
+	tree    *tview.TreeView
+	details *tview.TextView
+	queues  *tview.Table
+	status  *tview.TextView
+	panes   []tview.Primitive
+	fetch   Fetcher
+	history *history
+	opts    cli.Options
+	linter  *lint.Engine
+	// findings are those of the last topology, empty without linter.
+	findings findingIndex
+}
+
+// New builds the browser; the topology is loaded by fetch when Run is called
+// and then every opts.RefreshInterval, if set.
+//
+// When linter is not nil, each topology is linted and the objects with findings
+// are badged in the tree, their findings listed in the detail pane.
+func New(fetch Fetcher, opts cli.Options, linter *lint.Engine) *App {
+	a := &App{
+		app:     tview.NewApplication(),
+		tree:    tview.NewTreeView(),
+		details: tview.NewTextView().SetDynamicColors(true).SetWrap(true),
+		queues:  tview.NewTable().SetFixed(1, 0).SetSelectable(true, false),
+		status:  tview.NewTextView().SetDynamicColors(true),
+		fetch:   fetch,
+		history: newHistory(historySize),
+		opts:    opts,
+		linter:  linter,
+	}
+	a.panes = []tview.Primitive{a.tree, a.details, a.queues}
+
+	a.tree.SetBorder(true).SetTitle(" Topology ")
+	a.details.SetBorder(true).SetTitle(" Details ")
+	a.queues.SetBorder(true).SetTitle(" Queues ")
+
+	a.tree.SetChangedFunc(func(node *tview.TreeNode) {
+		a.details.SetText(a.describe(node.GetReference())).ScrollToBeginning()
+	})
+	a.tree.SetSelectedFunc(func(node *tview.TreeNode) {
+		node.SetExpanded(!node.IsExpanded())
+	})
+	a.details.SetText(describe(nil))
+
+	top := tview.NewFlex().
+		AddItem(a.tree, 0, 2, true).
+		AddItem(a.details, 0, 1, false)
+	layout := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(top, 0, 2, true).
+		AddItem(a.queues, 0, 1, false).
+		AddItem(a.status, 1, 0, false)
+
+	a.app.SetRoot(layout, true).SetInputCapture(a.handleKey)
+	return a
+}
+
+// Run loads the initial topology, starts the refresh loop and blocks until the
+// user quits or ctx is canceled.
+func (a *App) Run(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	topology, err := a.fetch(ctx)
+	if err != nil {
+		return err
+	}
+	a.update(topology)
+
+	go func() {
+		<-ctx.Done()
+		a.app.Stop()
+	}()
+	if a.opts.RefreshInterval > 0 {
+		go a.refreshLoop(ctx)
+	}
+	return a.app.Run()
+}
+
+// refreshLoop fetches the topology on every tick until ctx is canceled.
+func (a *App) refreshLoop(ctx context.Context) {
+	ticker := time.NewTicker(a.opts.RefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			topology, err := a.fetch(ctx)
+			if ctx.Err() != nil {
+				return
+			}
+			a.app.QueueUpdateDraw(func() {
+				if err != nil {
+					a.status.SetText(fmt.Sprintf("[red]refresh failed: %s[-]", tview.Escape(err.Error())))
+					return
+				}
+				a.update(topology)
+			})
+		}
+	}
+}
+
+// update replaces the displayed topology while preserving expanded nodes and the selection.
+func (a *App) update(topology *rabbitmq.Topology) {
+	a.history.record(topology)
+
+	if a.linter != nil {
+		a.findings = indexFindings(a.linter.Run(topology))
+	}
+
+	expanded, current := captureState(a.tree.GetRoot(), a.tree.GetCurrentNode())
+	root := buildTree(topology, a.opts)
+	a.findings.badge(root)
+	a.tree.SetRoot(root).SetCurrentNode(restoreState(root, expanded, current))
+	if node := a.tree.GetCurrentNode(); node != nil {
+		a.details.SetText(a.describe(node.GetReference()))
+	}
+
+	fillQueueTable(a.queues, topology, a.history)
+	a.status.SetText(a.statusLine(time.Now()))
+}
+
+// describe renders the object referenced by a tree node followed by its lint findings.
+func (a *App) describe(ref any) string {
+	return describe(ref) + a.findings.describe(ref)
+}
+
+// statusLine returns the help and refresh information shown at the bottom of the screen.
+func (a *App) statusLine(now time.Time) string {
+	line := " ↑/↓ navigate · Enter expand/collapse · Tab switch pane · q quit"
+	if a.opts.RefreshInterval > 0 {
+		line += fmt.Sprintf(" · refreshed %s (every %s)", now.Format(time.TimeOnly), a.opts.RefreshInterval)
+	}
+	return line
+}
+
+// handleKey implements global shortcuts: quitting and cycling focus between panes.
+func (a *App) handleKey(event *tcell.EventKey) *tcell.EventKey {
+	switch {
+	case event.Key() == tcell.KeyTab:
+		a.focusNext()
+		return nil
+	case event.Rune() == 'q':
+		a.app.Stop()
+		return nil
+	}
+	return event
+}
+
+// focusNext moves the focus to the pane after the focused one.
+func (a *App) focusNext() {
+	for i, p := range a.panes {
+		if p.HasFocus() {
+			a.app.SetFocus(a.panes[(i+1)%len(a.panes)])
+			return
+		}
+	}
+	a.app.SetFocus(a.tree)
+}
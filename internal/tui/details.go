@@ -0,0 +1,97 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Patrick-Ivann/AIM-Q/internal/rabbitmq"
+	"github.com/rivo/tview"
+)
+
+// describe renders the fields of the object referenced by a tree node for the detail pane.
+func describe(ref any) string {
+	var sb strings.Builder
+
+	switch v := ref.(type) {
+	case string:
+		writeField(&sb, "vhost", v)
+	case rabbitmq.Exchange:
+		writeField(&sb, "exchange", v.Name)
+		writeField(&sb, "vhost", v.Vhost)
+		writeField(&sb, "type", v.Type)
+		writeField(&sb, "durable", v.Durable)
+		writeField(&sb, "auto_delete", v.AutoDelete)
+		writeArguments(&sb, v.Arguments)
+		writePolicies(&sb, v.Policy, "", v.EffectiveDefinition)
+	case rabbitmq.Queue:
+		writeField(&sb, "queue", v.Name)
+		writeField(&sb, "vhost", v.Vhost)
+		writeField(&sb, "durable", v.Durable)
+		writeField(&sb, "auto_delete", v.AutoDelete)
+		writeField(&sb, "messages", v.MessageStats.Messages)
+		writeField(&sb, "ready", v.MessageStats.MessagesReady)
+		writeField(&sb, "unacked", v.MessageStats.MessagesUnacked)
+		writeArguments(&sb, v.Arguments)
+		writePolicies(&sb, v.Policy, v.OperatorPolicy, v.EffectiveDefinition)
+	case rabbitmq.Binding:
+		writeField(&sb, "source", v.Source)
+		writeField(&sb, "destination", v.Destination)
+		writeField(&sb, "destination_type", v.DestType)
+		writeField(&sb, "vhost", v.Vhost)
+		writeField(&sb, "routing_key", v.RoutingKey)
+	case rabbitmq.Consumer:
+		writeField(&sb, "consumer_tag", v.ConsumerTag)
+		writeField(&sb, "queue", v.Queue)
+		writeField(&sb, "vhost", v.Vhost)
+		writeField(&sb, "channel_pid", v.ChannelDetail.PID)
+	default:
+		sb.WriteString("Select an object to see its details.")
+	}
+	return sb.String()
+}
+
+// writeField writes a single highlighted "name: value" line.
+func writeField(sb *strings.Builder, name string, value any) {
+	sb.WriteString(fmt.Sprintf("[yellow]%s:[-] %s\n", name, tview.Escape(fmt.Sprint(value))))
+}
+
+// writeArguments writes the arguments map sorted by key.
+func writeArguments(sb *strings.Builder, args map[string]any) {
+	writeMap(sb, "arguments", args)
+}
+
+// writePolicies writes the policies applying to an exchange or queue and their
+// effective definition, if any.
+func writePolicies(sb *strings.Builder, policy, operatorPolicy string, definition map[string]any) {
+	if policy == "" && operatorPolicy == "" {
+		return
+	}
+	sb.WriteString("\n")
+	if policy != "" {
+		writeField(sb, "policy", policy)
+	}
+	if operatorPolicy != "" {
+		writeField(sb, "operator_policy", operatorPolicy)
+	}
+	writeMap(sb, "effective policy definition", definition)
+}
+
+// writeMap writes a titled map sorted by key.
+func writeMap(sb *strings.Builder, title string, args map[string]any) {
+	sb.WriteString("\n[yellow]" + title + ":[-]")
+	if len(args) == 0 {
+		sb.WriteString(" none\n")
+		return
+	}
+	sb.WriteString("\n")
+
+	keys := make([]string, 0, len(args))
+	for k := range args {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		sb.WriteString(fmt.Sprintf("  %s = %s\n", tview.Escape(k), tview.Escape(fmt.Sprint(args[k]))))
+	}
+}
@@ -0,0 +1,114 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/Patrick-Ivann/AIM-Q/internal/rabbitmq"
+)
+
+// sparkBlocks are the glyphs used to draw sparklines, from lowest to highest.
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// sample is a single observation of a queue's message counters.
+type sample struct {
+	messages int
+	ready    int
+	unacked  int
+}
+
+// history keeps a rolling window of message counter samples for every queue.
+type history struct {
+	size    int
+	samples map[string][]sample
+}
+
+// newHistory returns a history keeping at most size samples per queue.
+func newHistory(size int) *history {
+	if size < 1 {
+		size = 1
+	}
+	return &history{size: size, samples: make(map[string][]sample)}
+}
+
+// record appends the current counters of every queue in the topology.
+//
+// Queues that disappeared from the topology are forgotten.
+func (h *history) record(topology *rabbitmq.Topology) {
+	seen := make(map[string]struct{}, len(topology.Queues))
+	for _, q := range topology.Queues {
+		key := queueKey(q)
+		seen[key] = struct{}{}
+
+		series := append(h.samples[key], sample{
+			messages: q.MessageStats.Messages,
+			ready:    q.MessageStats.MessagesReady,
+			unacked:  q.MessageStats.MessagesUnacked,
+		})
+		if len(series) > h.size {
+			series = series[len(series)-h.size:]
+		}
+		h.samples[key] = series
+	}
+	for key := range h.samples {
+		if _, ok := seen[key]; !ok {
+			delete(h.samples, key)
+		}
+	}
+}
+
+// series returns the recorded samples of a queue, oldest first.
+func (h *history) series(q rabbitmq.Queue) []sample {
+	return h.samples[queueKey(q)]
+}
+
+// queueKey identifies a queue across refreshes.
+func queueKey(q rabbitmq.Queue) string {
+	return q.Vhost + "/" + q.Name
+}
+
+// counters extracts one counter from a series of samples.
+func counters(series []sample, pick func(sample) int) []int {
+	values := make([]int, len(series))
+	for i, s := range series {
+		values[i] = pick(s)
+	}
+	return values
+}
+
+// sparkline renders values as a row of block glyphs scaled between their min and max.
+func sparkline(values []int) string {
+	if len(values) == 0 {
+		return ""
+	}
+	lo, hi := values[0], values[0]
+	for _, v := range values {
+		lo = min(lo, v)
+		hi = max(hi, v)
+	}
+
+	var sb strings.Builder
+	for _, v := range values {
+		idx := 0
+		if hi > lo {
+			idx = (v - lo) * (len(sparkBlocks) - 1) / (hi - lo)
+		}
+		sb.WriteRune(sparkBlocks[idx])
+	}
+	return sb.String()
+}
+
+// trend compares the last two values and returns an arrow describing the direction.
+func trend(values []int) string {
+	if len(values) < 2 {
+		return "→"
+	}
+	last, prev := values[len(values)-1], values[len(values)-2]
+	switch {
+	case last > prev:
+		return "↑"
+	case last < prev:
+		return "↓"
+	default:
+		return "→"
+	}
+}
@@ -0,0 +1,66 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/Patrick-Ivann/AIM-Q/internal/cli"
+	"github.com/Patrick-Ivann/AIM-Q/internal/rabbitmq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func queueWithMessages(name string, messages int) rabbitmq.Queue {
+	q := rabbitmq.Queue{Name: name, Vhost: "/"}
+	q.MessageStats.Messages = messages
+	q.MessageStats.MessagesReady = messages / 2
+	return q
+}
+
+func TestHistory_RecordKeepsRollingWindow(t *testing.T) {
+	h := newHistory(3)
+	for i := 1; i <= 5; i++ {
+		h.record(&rabbitmq.Topology{Queues: []rabbitmq.Queue{queueWithMessages("q1", i*10)}})
+	}
+
+	series := h.series(queueWithMessages("q1", 0))
+	require.Len(t, series, 3)
+	assert.Equal(t, []int{30, 40, 50}, counters(series, func(s sample) int { return s.messages }))
+	assert.Equal(t, []int{15, 20, 25}, counters(series, func(s sample) int { return s.ready }))
+}
+
+func TestHistory_RecordForgetsDeletedQueues(t *testing.T) {
+	h := newHistory(3)
+	h.record(&rabbitmq.Topology{Queues: []rabbitmq.Queue{queueWithMessages("q1", 1), queueWithMessages("q2", 2)}})
+	h.record(&rabbitmq.Topology{Queues: []rabbitmq.Queue{queueWithMessages("q2", 3)}})
+
+	assert.Empty(t, h.series(queueWithMessages("q1", 0)))
+	assert.Len(t, h.series(queueWithMessages("q2", 0)), 2)
+}
+
+func TestSparkline(t *testing.T) {
+	assert.Equal(t, "", sparkline(nil))
+	assert.Equal(t, "▁▁▁", sparkline([]int{5, 5, 5}))
+	assert.Equal(t, "▁▄█", sparkline([]int{0, 50, 100}))
+	assert.Equal(t, "█▁", sparkline([]int{10, 2}))
+}
+
+func TestTrend(t *testing.T) {
+	assert.Equal(t, "→", trend([]int{1}))
+	assert.Equal(t, "↑", trend([]int{1, 2}))
+	assert.Equal(t, "↓", trend([]int{2, 1}))
+	assert.Equal(t, "→", trend([]int{3, 3}))
+}
+
+func TestRestoreState_PreservesExpansionAndSelection(t *testing.T) {
+	root := buildTree(testTopology(), cli.Options{})
+	orders := root.GetChildren()[0].GetChildren()[1]
+	orders.SetExpanded(true)
+	selected := orders.GetChildren()[0]
+
+	expanded, current := captureState(root, selected)
+	rebuilt := buildTree(testTopology(), cli.Options{})
+	node := restoreState(rebuilt, expanded, current)
+
+	assert.True(t, rebuilt.GetChildren()[0].GetChildren()[1].IsExpanded())
+	assert.Equal(t, selected.GetReference(), node.GetReference())
+}
@@ -0,0 +1,75 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Patrick-Ivann/AIM-Q/internal/lint"
+	"github.com/Patrick-Ivann/AIM-Q/internal/rabbitmq"
+	"github.com/rivo/tview"
+)
+
+// severityColors are the tview colors of lint severities.
+var severityColors = map[lint.Severity]string{
+	lint.Info:    "blue",
+	lint.Warning: "orange",
+	lint.Error:   "red",
+}
+
+// findingIndex groups lint findings by the object they are about, keyed by findingKey.
+type findingIndex map[string][]lint.Finding
+
+// indexFindings indexes the findings of a report; they stay sorted by
+// decreasing severity, so the first finding of an object is the most serious.
+func indexFindings(report *lint.Report) findingIndex {
+	idx := make(findingIndex)
+	for _, f := range report.Findings {
+		key := f.Kind + ":" + f.Vhost + "/" + f.Name
+		idx[key] = append(idx[key], f)
+	}
+	return idx
+}
+
+// findingKey returns the key of the findings about the object referenced by a
+// tree node, "" if lint rules do not check such objects.
+func findingKey(ref any) string {
+	switch v := ref.(type) {
+	case rabbitmq.Exchange:
+		return "exchange:" + v.Vhost + "/" + v.Name
+	case rabbitmq.Queue:
+		return "queue:" + v.Vhost + "/" + v.Name
+	case rabbitmq.Binding:
+		return "binding:" + v.Vhost + "/" + v.Source + " -> " + v.Destination
+	default:
+		return ""
+	}
+}
+
+// badge appends to the label of each node with findings a marker with their
+// count, colored by the highest severity.
+func (idx findingIndex) badge(root *tview.TreeNode) {
+	walkPaths(root, "", func(node *tview.TreeNode, _ string) {
+		findings := idx[findingKey(node.GetReference())]
+		if len(findings) == 0 {
+			return
+		}
+		node.SetText(fmt.Sprintf("%s [%s]⚠ %d[-]", node.GetText(), severityColors[findings[0].Severity], len(findings)))
+	})
+}
+
+// describe renders the findings about the object referenced by a tree node for
+// the detail pane, "" if there are none.
+func (idx findingIndex) describe(ref any) string {
+	findings := idx[findingKey(ref)]
+	if len(findings) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString("\n[yellow]lint findings:[-]\n")
+	for _, f := range findings {
+		sb.WriteString(fmt.Sprintf("  [%s]%s[-] %s: %s\n",
+			severityColors[f.Severity], f.Severity, f.Rule, tview.Escape(f.Message)))
+	}
+	return sb.String()
+}
@@ -0,0 +1,47 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/Patrick-Ivann/AIM-Q/internal/cli"
+	"github.com/Patrick-Ivann/AIM-Q/internal/lint"
+	"github.com/Patrick-Ivann/AIM-Q/internal/rabbitmq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindingIndex_Badge(t *testing.T) {
+	engine, err := lint.NewEngine(nil)
+	require.NoError(t, err)
+	topo := testTopology()
+	idx := indexFindings(engine.Run(topo))
+
+	root := buildTree(topo, cli.Options{})
+	idx.badge(root)
+
+	vh1 := root.GetChildren()[0]
+	audit, orders := vh1.GetChildren()[0], vh1.GetChildren()[1]
+	assert.Equal(t, "🔄 exchange: audit (fanout) [orange]⚠ 1[-]", audit.GetText())
+	assert.NotContains(t, orders.GetText(), "⚠")
+
+	queue := orders.GetChildren()[0].GetChildren()[0]
+	assert.Equal(t, "📦 queue: q1", queue.GetText())
+	q2 := root.GetChildren()[1].GetChildren()[0].GetChildren()[0].GetChildren()[0]
+	assert.Equal(t, "📦 queue: q2 [orange]⚠ 2[-]", q2.GetText())
+
+	out := idx.describe(q2.GetReference())
+	assert.Contains(t, out, "lint findings:")
+	assert.Contains(t, out, "  [orange]warning[-] queue-without-bindings: no exchange is bound to the queue\n")
+	assert.Contains(t, out, "  [blue]info[-] queue-without-consumers: ")
+	assert.Empty(t, idx.describe(queue.GetReference()))
+}
+
+func TestFindingKey_Binding(t *testing.T) {
+	engine, err := lint.NewEngine(nil)
+	require.NoError(t, err)
+	topo := testTopology()
+	topo.Bindings = append(topo.Bindings, rabbitmq.Binding{Source: "orders", Destination: "gone", DestType: "queue", Vhost: "vh1"})
+
+	idx := indexFindings(engine.Run(topo))
+	assert.Contains(t, idx.describe(topo.Bindings[3]), `[red]error[-] binding-missing-destination: destination queue "gone" does not exist`)
+}
@@ -0,0 +1,56 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/Patrick-Ivann/AIM-Q/internal/rabbitmq"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// queueColumns are the headers of the live queue table.
+var queueColumns = []string{"VHOST", "QUEUE", "MESSAGES", "READY", "UNACKED"}
+
+// fillQueueTable renders one row per queue with current counters, sparklines and trend arrows.
+func fillQueueTable(table *tview.Table, topology *rabbitmq.Topology, hist *history) {
+	table.Clear()
+	for col, title := range queueColumns {
+		table.SetCell(0, col, tview.NewTableCell(title).
+			SetTextColor(tcell.ColorYellow).
+			SetSelectable(false))
+	}
+
+	queues := append([]rabbitmq.Queue(nil), topology.Queues...)
+	sort.Slice(queues, func(i, j int) bool { return queueKey(queues[i]) < queueKey(queues[j]) })
+
+	for i, q := range queues {
+		row := i + 1
+		series := hist.series(q)
+		table.SetCell(row, 0, tview.NewTableCell(tview.Escape(q.Vhost)))
+		table.SetCell(row, 1, tview.NewTableCell(tview.Escape(q.Name)).SetExpansion(1))
+		table.SetCell(row, 2, counterCell(counters(series, func(s sample) int { return s.messages })))
+		table.SetCell(row, 3, counterCell(counters(series, func(s sample) int { return s.ready })))
+		table.SetCell(row, 4, counterCell(counters(series, func(s sample) int { return s.unacked })))
+	}
+}
+
+// counterCell renders the latest value of a counter followed by its sparkline and trend.
+//
+// A growing counter is shown in red and a draining one in green.
+func counterCell(values []int) *tview.TableCell {
+	if len(values) == 0 {
+		return tview.NewTableCell("-")
+	}
+	arrow := trend(values)
+	text := fmt.Sprintf("%d %s %s", values[len(values)-1], sparkline(values), arrow)
+
+	cell := tview.NewTableCell(text)
+	switch arrow {
+	case "↑":
+		cell.SetTextColor(tcell.ColorRed)
+	case "↓":
+		cell.SetTextColor(tcell.ColorGreen)
+	}
+	return cell
+}
@@ -0,0 +1,216 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/Patrick-Ivann/AIM-Q/internal/cli"
+	"github.com/Patrick-Ivann/AIM-Q/internal/rabbitmq"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// defaultExchangeLabel is displayed for the nameless default exchange.
+const defaultExchangeLabel = "(default)"
+
+// buildTree creates the navigable hierarchy vhosts → exchanges → bindings → queues → consumers.
+//
+// Queues no exchange of the tree is bound to are listed directly under their
+// vhost, after the exchanges, so every queue can be browsed.
+// Each node references the underlying RabbitMQ object so the detail pane can render it.
+func buildTree(topology *rabbitmq.Topology, opts cli.Options) *tview.TreeNode {
+	root := tview.NewTreeNode("RabbitMQ").SetColor(tcell.ColorYellow)
+
+	for _, vhost := range vhosts(topology) {
+		vhostNode := tview.NewTreeNode("🏠 vhost: " + tview.Escape(vhost)).
+			SetReference(vhost).
+			SetColor(tcell.ColorGreen)
+		exchanges := exchangesIn(topology, vhost)
+		for _, ex := range exchanges {
+			vhostNode.AddChild(exchangeNode(topology, opts, ex))
+		}
+		for _, q := range unboundQueues(topology, vhost, exchanges) {
+			vhostNode.AddChild(queueNode(topology, opts, q))
+		}
+		root.AddChild(vhostNode)
+	}
+	return root
+}
+
+// exchangeNode creates a collapsed exchange node with one child per outgoing binding.
+func exchangeNode(topology *rabbitmq.Topology, opts cli.Options, ex rabbitmq.Exchange) *tview.TreeNode {
+	name := ex.Name
+	if name == "" {
+		name = defaultExchangeLabel
+	}
+	node := tview.NewTreeNode(fmt.Sprintf("%s exchange: %s (%s)", rabbitmq.ExchangeIcon(ex.Type), tview.Escape(name), ex.Type)).
+		SetReference(ex).
+		SetExpanded(false)
+
+	for _, b := range topology.Bindings {
+		if b.Vhost != ex.Vhost || b.Source != ex.Name {
+			continue
+		}
+		node.AddChild(bindingNode(topology, opts, b))
+	}
+	return node
+}
+
+// bindingNode creates a binding node; queue destinations are nested below it with their consumers.
+func bindingNode(topology *rabbitmq.Topology, opts cli.Options, b rabbitmq.Binding) *tview.TreeNode {
+	label := fmt.Sprintf("🔗 → %s: %s", b.DestType, tview.Escape(b.Destination))
+	if b.RoutingKey != "" {
+		label += fmt.Sprintf(" [key: %s]", tview.Escape(b.RoutingKey))
+	}
+	node := tview.NewTreeNode(label).SetReference(b).SetExpanded(false)
+
+	// Exchange-to-exchange destinations are not expanded to avoid cycles.
+	if b.DestType != "queue" {
+		return node
+	}
+	q, ok := findQueue(topology, b.Vhost, b.Destination)
+	if !ok {
+		return node
+	}
+	node.AddChild(queueNode(topology, opts, q))
+	return node
+}
+
+// queueNode creates a queue node with one child per consumer.
+func queueNode(topology *rabbitmq.Topology, opts cli.Options, q rabbitmq.Queue) *tview.TreeNode {
+	label := "📦 queue: " + tview.Escape(q.Name)
+	if opts.ShowMsgStats {
+		label += fmt.Sprintf(" (msgs: %d)", q.MessageStats.Messages)
+	}
+	node := tview.NewTreeNode(label).SetReference(q).SetColor(tcell.ColorAqua)
+
+	for _, c := range topology.Consumers {
+		if c.Vhost != q.Vhost || c.Queue != q.Name {
+			continue
+		}
+		node.AddChild(tview.NewTreeNode("👤 consumer: " + tview.Escape(c.ConsumerTag)).SetReference(c))
+	}
+	return node
+}
+
+// captureState records which nodes are expanded and which one is selected.
+//
+// Nodes are identified by the path of their references from the root, so the
+// state survives a rebuild of the tree from a refreshed topology.
+func captureState(root, current *tview.TreeNode) (expanded map[string]bool, selected string) {
+	expanded = make(map[string]bool)
+	walkPaths(root, "", func(node *tview.TreeNode, path string) {
+		expanded[path] = node.IsExpanded()
+		if node == current {
+			selected = path
+		}
+	})
+	return expanded, selected
+}
+
+// restoreState applies a captured state to a rebuilt tree and returns the node to select.
+func restoreState(root *tview.TreeNode, expanded map[string]bool, selected string) *tview.TreeNode {
+	current := root
+	walkPaths(root, "", func(node *tview.TreeNode, path string) {
+		if isExpanded, ok := expanded[path]; ok {
+			node.SetExpanded(isExpanded)
+		}
+		if path == selected {
+			current = node
+		}
+	})
+	return current
+}
+
+// walkPaths visits every node depth-first along with its reference path.
+func walkPaths(node *tview.TreeNode, parent string, visit func(*tview.TreeNode, string)) {
+	if node == nil {
+		return
+	}
+	path := parent + "|" + nodeKey(node.GetReference())
+	visit(node, path)
+	for _, child := range node.GetChildren() {
+		walkPaths(child, path, visit)
+	}
+}
+
+// nodeKey returns a stable identifier for the object referenced by a node.
+func nodeKey(ref any) string {
+	switch v := ref.(type) {
+	case string:
+		return "vhost:" + v
+	case rabbitmq.Exchange:
+		return "exchange:" + v.Vhost + "/" + v.Name
+	case rabbitmq.Queue:
+		return "queue:" + v.Vhost + "/" + v.Name
+	case rabbitmq.Binding:
+		return fmt.Sprintf("binding:%s/%s/%s:%s/%s", v.Vhost, v.Source, v.DestType, v.Destination, v.RoutingKey)
+	case rabbitmq.Consumer:
+		return "consumer:" + v.Vhost + "/" + v.ConsumerTag
+	default:
+		return "root"
+	}
+}
+
+// vhosts returns the sorted list of virtual hosts present in the topology.
+func vhosts(topology *rabbitmq.Topology) []string {
+	set := make(map[string]struct{})
+	for _, ex := range topology.Exchanges {
+		set[ex.Vhost] = struct{}{}
+	}
+	for _, q := range topology.Queues {
+		set[q.Vhost] = struct{}{}
+	}
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// exchangesIn returns the exchanges of a vhost sorted by name.
+func exchangesIn(topology *rabbitmq.Topology, vhost string) []rabbitmq.Exchange {
+	var result []rabbitmq.Exchange
+	for _, ex := range topology.Exchanges {
+		if ex.Vhost == vhost {
+			result = append(result, ex)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+// unboundQueues returns the queues of a vhost, sorted by name, that none of
+// the exchanges is bound to.
+func unboundQueues(topology *rabbitmq.Topology, vhost string, exchanges []rabbitmq.Exchange) []rabbitmq.Queue {
+	sources := make(map[string]struct{}, len(exchanges))
+	for _, ex := range exchanges {
+		sources[ex.Name] = struct{}{}
+	}
+	bound := make(map[string]struct{})
+	for _, b := range topology.Bindings {
+		if _, ok := sources[b.Source]; ok && b.Vhost == vhost && b.DestType == "queue" {
+			bound[b.Destination] = struct{}{}
+		}
+	}
+
+	var result []rabbitmq.Queue
+	for _, q := range topology.Queues {
+		if _, ok := bound[q.Name]; !ok && q.Vhost == vhost {
+			result = append(result, q)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+// findQueue looks up a queue by vhost and name.
+func findQueue(topology *rabbitmq.Topology, vhost, name string) (rabbitmq.Queue, bool) {
+	for _, q := range topology.Queues {
+		if q.Vhost == vhost && q.Name == name {
+			return q, true
+		}
+	}
+	return rabbitmq.Queue{}, false
+}
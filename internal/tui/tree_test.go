@@ -0,0 +1,113 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Patrick-Ivann/AIM-Q/internal/cli"
+	"github.com/Patrick-Ivann/AIM-Q/internal/rabbitmq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testTopology() *rabbitmq.Topology {
+	return &rabbitmq.Topology{
+		Exchanges: []rabbitmq.Exchange{
+			{Name: "orders", Vhost: "vh1", Type: "topic"},
+			{Name: "audit", Vhost: "vh1", Type: "fanout"},
+			{Name: "", Vhost: "vh2", Type: "direct"},
+		},
+		Queues: []rabbitmq.Queue{
+			{Name: "q1", Vhost: "vh1"},
+			{Name: "q2", Vhost: "vh2"},
+		},
+		Bindings: []rabbitmq.Binding{
+			{Source: "orders", Destination: "q1", DestType: "queue", Vhost: "vh1", RoutingKey: "order.*"},
+			{Source: "orders", Destination: "audit", DestType: "exchange", Vhost: "vh1"},
+			{Source: "", Destination: "q2", DestType: "queue", Vhost: "vh2", RoutingKey: "q2"},
+		},
+		Consumers: []rabbitmq.Consumer{
+			{Queue: "q1", Vhost: "vh1", ConsumerTag: "ctag1"},
+		},
+	}
+}
+
+func TestBuildTree_Hierarchy(t *testing.T) {
+	root := buildTree(testTopology(), cli.Options{})
+
+	vhostNodes := root.GetChildren()
+	require.Len(t, vhostNodes, 2)
+	assert.Equal(t, "vh1", vhostNodes[0].GetReference())
+	assert.Equal(t, "vh2", vhostNodes[1].GetReference())
+
+	// Exchanges are sorted by name within the vhost.
+	exchanges := vhostNodes[0].GetChildren()
+	require.Len(t, exchanges, 2)
+	assert.Equal(t, "audit", exchanges[0].GetReference().(rabbitmq.Exchange).Name)
+	orders := exchanges[1]
+	assert.Equal(t, "orders", orders.GetReference().(rabbitmq.Exchange).Name)
+
+	bindings := orders.GetChildren()
+	require.Len(t, bindings, 2)
+	assert.Contains(t, bindings[0].GetText(), "order.*")
+
+	// Queue destinations are nested with their consumers; exchange destinations are leaves.
+	queues := bindings[0].GetChildren()
+	require.Len(t, queues, 1)
+	assert.Equal(t, "q1", queues[0].GetReference().(rabbitmq.Queue).Name)
+	consumers := queues[0].GetChildren()
+	require.Len(t, consumers, 1)
+	assert.Equal(t, "ctag1", consumers[0].GetReference().(rabbitmq.Consumer).ConsumerTag)
+	assert.Empty(t, bindings[1].GetChildren())
+
+	defaultExchange := vhostNodes[1].GetChildren()[0]
+	assert.Contains(t, defaultExchange.GetText(), defaultExchangeLabel)
+}
+
+func TestBuildTree_UnboundQueues(t *testing.T) {
+	topo := testTopology()
+	topo.Queues = append(topo.Queues, rabbitmq.Queue{Name: "orphan", Vhost: "vh1"})
+	// Bound from an exchange missing from the tree, e.g. hidden by --filter-exchange.
+	topo.Queues = append(topo.Queues, rabbitmq.Queue{Name: "hidden", Vhost: "vh1"})
+	topo.Bindings = append(topo.Bindings,
+		rabbitmq.Binding{Source: "filtered", Destination: "hidden", DestType: "queue", Vhost: "vh1"})
+
+	vh1 := buildTree(topo, cli.Options{}).GetChildren()[0].GetChildren()
+	require.Len(t, vh1, 4)
+	assert.Equal(t, "hidden", vh1[2].GetReference().(rabbitmq.Queue).Name)
+	assert.Equal(t, "orphan", vh1[3].GetReference().(rabbitmq.Queue).Name)
+	assert.Contains(t, vh1[3].GetText(), "📦 queue: orphan")
+}
+
+func TestBuildTree_MessageStats(t *testing.T) {
+	topo := testTopology()
+	topo.Queues[0].MessageStats.Messages = 42
+
+	root := buildTree(topo, cli.Options{ShowMsgStats: true})
+	queue := root.GetChildren()[0].GetChildren()[1].GetChildren()[0].GetChildren()[0]
+	assert.Contains(t, queue.GetText(), "msgs: 42")
+}
+
+func TestDescribe(t *testing.T) {
+	q := rabbitmq.Queue{Name: "q1", Vhost: "vh1", Arguments: map[string]any{"x-b": 2, "x-a": "one"}}
+	out := describe(q)
+	assert.Contains(t, out, "q1")
+	assert.Less(t, strings.Index(out, "x-a"), strings.Index(out, "x-b"))
+
+	assert.Contains(t, describe(rabbitmq.Exchange{Name: "ex"}), "arguments:[-] none")
+	assert.Contains(t, describe(nil), "Select an object")
+	assert.NotContains(t, out, "policy")
+}
+
+func TestDescribe_Policies(t *testing.T) {
+	q := rabbitmq.Queue{
+		Name:                "q1",
+		Policy:              "dlx",
+		OperatorPolicy:      "limits",
+		EffectiveDefinition: map[string]any{"dead-letter-exchange": "dlx", "max-length": 1000},
+	}
+	out := describe(q)
+	assert.Contains(t, out, "policy:[-] dlx")
+	assert.Contains(t, out, "operator_policy:[-] limits")
+	assert.Contains(t, out, "  max-length = 1000\n")
+}
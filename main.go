@@ -4,10 +4,11 @@ import (
 	"log"
 
 	"github.com/Patrick-Ivann/AIM-Q/cmd"
+	"github.com/Patrick-Ivann/AIM-Q/internal/redact"
 )
 
 func main() {
 	if err := cmd.Execute(); err != nil {
-		log.Fatalf("❌ Command failed: %v", err)
+		log.Fatalf("❌ Command failed: %v", redact.String(err.Error()))
 	}
 }
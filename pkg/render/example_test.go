@@ -0,0 +1,41 @@
+package render_test
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Patrick-Ivann/AIM-Q/pkg/render"
+)
+
+// A renderer package of another module registers its formats from init.
+func init() {
+	render.Register("example-queues", render.NewRenderer("txt", queueList))
+}
+
+// queueList renders one line per queue. Like a renderer of another module, it
+// only uses the render package to name the topology types.
+func queueList(topology *render.Topology, _ render.Options) ([]byte, error) {
+	var sb strings.Builder
+	for _, q := range topology.Queues {
+		sb.WriteString(describeQueue(q) + "\n")
+	}
+	return []byte(sb.String()), nil
+}
+
+// describeQueue is a helper over an element type of the topology.
+func describeQueue(q render.Queue) string {
+	return fmt.Sprintf("%s%s (durable=%t)", q.Vhost, q.Name, q.Durable)
+}
+
+func Example() {
+	r, err := render.Lookup("example-queues")
+	if err != nil {
+		panic(err)
+	}
+	out, err := r.Render(&render.Topology{Queues: []render.Queue{{Name: "orders", Vhost: "/", Durable: true}}}, render.Options{})
+	if err != nil {
+		panic(err)
+	}
+	fmt.Print(string(out))
+	// Output: /orders (durable=true)
+}
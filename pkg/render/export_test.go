@@ -0,0 +1,4 @@
+package render
+
+// Unregister exposes unregister to the tests of render_test.
+var Unregister = unregister
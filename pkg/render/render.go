@@ -0,0 +1,138 @@
+// Package render defines the Renderer interface of the generate command's
+// output formats and the registry selecting them by name with --format.
+//
+// The built-in formats are registered by the diagram package. Other modules
+// can add their own: a package registering a Renderer from its init function
+// becomes selectable once it is imported next to the cmd package by a main package:
+//
+//	import (
+//		"github.com/Patrick-Ivann/AIM-Q/cmd"
+//		_ "example.com/aimq-svg" // calls render.Register("svg", ...)
+//	)
+//
+//	func main() {
+//		if err := cmd.Execute(); err != nil {
+//			log.Fatal(err)
+//		}
+//	}
+package render
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/Patrick-Ivann/AIM-Q/internal/cli"
+	"github.com/Patrick-Ivann/AIM-Q/internal/rabbitmq"
+)
+
+// The types a renderer works with, aliased from internal packages so that
+// renderers of other modules can name them.
+type (
+	// Topology is the RabbitMQ topology passed to renderers.
+	Topology = rabbitmq.Topology
+	// Options are the generate command options passed to renderers.
+	Options = cli.Options
+
+	Exchange       = rabbitmq.Exchange       // Exchange of Topology.Exchanges
+	Queue          = rabbitmq.Queue          // Queue of Topology.Queues
+	Binding        = rabbitmq.Binding        // Binding of Topology.Bindings
+	Consumer       = rabbitmq.Consumer       // Consumer of Topology.Consumers
+	Policy         = rabbitmq.Policy         // Policy of Topology.Policies and Topology.OperatorPolicies
+	Shovel         = rabbitmq.Shovel         // Shovel of Topology.Shovels
+	FederationLink = rabbitmq.FederationLink // Federation link of Topology.FederationLinks
+)
+
+// Renderer turns a RabbitMQ topology into an output document of a given format.
+//
+// Implementations are made available to the generate command by registering
+// them under a format name with Register, typically from an init function.
+type Renderer interface {
+	// Render returns the document for the topology.
+	Render(topology *Topology, opts Options) ([]byte, error)
+	// Extension returns the conventional file extension of the output, without the dot.
+	Extension() string
+}
+
+// RenderFunc is the signature of a function rendering a topology.
+type RenderFunc func(topology *Topology, opts Options) ([]byte, error)
+
+// funcRenderer adapts a RenderFunc and an extension to the Renderer interface.
+type funcRenderer struct {
+	extension string
+	render    RenderFunc
+}
+
+// Render implements Renderer.
+func (r funcRenderer) Render(topology *Topology, opts Options) ([]byte, error) {
+	return r.render(topology, opts)
+}
+
+// Extension implements Renderer.
+func (r funcRenderer) Extension() string {
+	return r.extension
+}
+
+// NewRenderer returns a Renderer producing files with the given extension using render.
+func NewRenderer(extension string, render RenderFunc) Renderer {
+	return funcRenderer{extension: extension, render: render}
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Renderer)
+)
+
+// Register makes a renderer available under the given format name.
+//
+// It panics if format is empty, if r is nil or if the format is already registered,
+// mirroring database/sql.Register.
+func Register(format string, r Renderer) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if format == "" {
+		panic("render: Register called with an empty format")
+	}
+	if r == nil {
+		panic("render: Register renderer is nil for format " + format)
+	}
+	if _, dup := registry[format]; dup {
+		panic("render: Register called twice for format " + format)
+	}
+	registry[format] = r
+}
+
+// unregister removes the renderer of a format, so tests can register their own.
+func unregister(format string) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	delete(registry, format)
+}
+
+// Lookup returns the renderer registered under the given format name.
+func Lookup(format string) (Renderer, error) {
+	registryMu.RLock()
+	r, ok := registry[format]
+	registryMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("unknown format %q (available: %s)", format, strings.Join(Formats(), ", "))
+	}
+	return r, nil
+}
+
+// Formats returns the sorted names of all registered formats.
+func Formats() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	formats := make([]string, 0, len(registry))
+	for format := range registry {
+		formats = append(formats, format)
+	}
+	sort.Strings(formats)
+	return formats
+}
@@ -0,0 +1,53 @@
+package render_test
+
+import (
+	"testing"
+
+	"github.com/Patrick-Ivann/AIM-Q/internal/rabbitmq"
+	"github.com/Patrick-Ivann/AIM-Q/pkg/render"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// register registers r under format for the duration of the test.
+func register(t *testing.T, format string, r render.Renderer) {
+	t.Helper()
+	render.Register(format, r)
+	t.Cleanup(func() { render.Unregister(format) })
+}
+
+// countQueues renders the number of queues of the topology.
+func countQueues(topology *render.Topology, _ render.Options) ([]byte, error) {
+	return []byte{byte('0' + len(topology.Queues))}, nil
+}
+
+func TestRegister_CustomRenderer(t *testing.T) {
+	register(t, "test-count", render.NewRenderer("txt", countQueues))
+
+	assert.Contains(t, render.Formats(), "test-count")
+
+	r, err := render.Lookup("test-count")
+	require.NoError(t, err)
+	assert.Equal(t, "txt", r.Extension())
+	out, err := r.Render(&rabbitmq.Topology{Queues: []rabbitmq.Queue{{Name: "a"}, {Name: "b"}}}, render.Options{})
+	require.NoError(t, err)
+	assert.Equal(t, "2", string(out))
+}
+
+func TestLookup_UnknownFormat(t *testing.T) {
+	register(t, "test-known", render.NewRenderer("txt", countQueues))
+
+	_, err := render.Lookup("svg")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown format "svg"`)
+	assert.Contains(t, err.Error(), "test-known")
+}
+
+func TestRegister_Panics(t *testing.T) {
+	noop := render.NewRenderer("txt", countQueues)
+	register(t, "test-dup", noop)
+
+	assert.Panics(t, func() { render.Register("test-dup", noop) })
+	assert.Panics(t, func() { render.Register("", noop) })
+	assert.Panics(t, func() { render.Register("nil-renderer", nil) })
+}